@@ -6,31 +6,111 @@
 package cache
 
 import (
+	"context"
 	"iter"
 	"sync"
+	"time"
 
 	"go.osspkg.com/random"
+
+	"go.osspkg.com/ioutils/codec"
 )
 
 type (
 	_cache[K comparable, V any] struct {
-		list map[K]V
-		mux  sync.RWMutex
+		list       map[K]entry[V]
+		mux        sync.RWMutex
+		policy     policy[K]
+		maxCount   int
+		defaultTTL time.Duration
+		// parts count of shards sharing the limits of options
+		parts int
+
+		loadMux  sync.Mutex
+		calls    map[K]*call[V]
+		errs     map[K]failure
+		errorTTL time.Duration
+
+		softTTL      time.Duration
+		refreshCtx   context.Context
+		refresher    Loader[K, V]
+		onRefreshErr func(K, error)
+
+		onEvict func(K, V, EvictReason)
+		events  []event[K, V]
+
+		snapshot snapshotConfig
+		done     <-chan struct{}
+
+		costFn func(V) int64
+		// maxCost is the limit of the shard, limitCost is the limit of the whole cache
+		maxCost   int64
+		limitCost int64
+		cost      int64
+	}
+
+	entry[V any] struct {
+		val V
+		// exp expiration time in unix nanoseconds, 0 if the entry never expires
+		exp int64
+		// soft time in unix nanoseconds after which the entry is refreshed, 0 if never
+		soft int64
+		cost int64
 	}
 )
 
+func (e entry[V]) expired(now int64) bool {
+	return e.exp > 0 && e.exp <= now
+}
+
+func (e entry[V]) stale(now int64) bool {
+	return e.soft > 0 && e.soft <= now
+}
+
+func expiration(ttl time.Duration) int64 {
+	if ttl <= 0 {
+		return 0
+	}
+	return time.Now().Add(ttl).UnixNano()
+}
+
 func New[K comparable, V any](opts ...Option[K, V]) Cache[K, V] {
+	obj := newCache(1, opts...)
+	obj.done = persist[K, V](obj.snapshot, obj)
+
+	return obj
+}
+
+func newCache[K comparable, V any](parts int, opts ...Option[K, V]) *_cache[K, V] {
 	obj := &_cache[K, V]{
-		list: make(map[K]V, 100),
+		list:  make(map[K]entry[V], 100),
+		calls: make(map[K]*call[V]),
+		parts: parts,
+		snapshot: snapshotConfig{
+			ext: codec.ExtGob,
+		},
 	}
 
 	for _, opt := range opts {
-		go opt(obj)
+		opt(obj)
+	}
+
+	if obj.maxCost > 0 && obj.policy == nil {
+		obj.policy = newLRU[K](0)
 	}
 
 	return obj
 }
 
+// minShare is the least capacity of one shard, the keys are not spread evenly
+// between shards and smaller shards evict elements long before the whole cache is full
+const minShare = 16
+
+// share returns the part of the limit for one shard
+func (v *_cache[K, V]) share(limit int) int {
+	return (limit + v.parts - 1) / v.parts
+}
+
 func (v *_cache[K, V]) Size() int {
 	v.mux.RLock()
 	defer v.mux.RUnlock()
@@ -42,22 +122,55 @@ func (v *_cache[K, V]) Has(key K) bool {
 	v.mux.RLock()
 	defer v.mux.RUnlock()
 
-	_, ok := v.list[key]
+	item, ok := v.list[key]
 
-	return ok
+	return ok && !item.expired(time.Now().UnixNano())
 }
 
 func (v *_cache[K, V]) Get(key K) (V, bool) {
+	val, ok, stale := v.get(key)
+	if stale {
+		v.refresh(key)
+	}
+
+	return val, ok
+}
+
+func (v *_cache[K, V]) get(key K) (val V, ok bool, stale bool) {
+	if v.policy == nil {
+		v.mux.RLock()
+		defer v.mux.RUnlock()
+	} else {
+		v.mux.Lock()
+		defer v.mux.Unlock()
+	}
+
+	now := time.Now().UnixNano()
+
+	item, ok := v.list[key]
+	if !ok || item.expired(now) {
+		return val, false, false
+	}
+
+	if v.policy != nil {
+		v.policy.touch(key)
+	}
+
+	return item.val, true, item.stale(now)
+}
+
+// peek gets the element without updating the eviction policy
+func (v *_cache[K, V]) peek(key K) (V, bool) {
 	v.mux.RLock()
 	defer v.mux.RUnlock()
 
 	item, ok := v.list[key]
-	if !ok {
+	if !ok || item.expired(time.Now().UnixNano()) {
 		var zeroValue V
 		return zeroValue, false
 	}
 
-	return item, true
+	return item.val, true
 }
 
 func (v *_cache[K, V]) One() (key K, val V, ok bool) {
@@ -69,12 +182,14 @@ func (v *_cache[K, V]) One() (key K, val V, ok bool) {
 	random.Shuffle(keys)
 
 	key = keys[0]
-	val, ok = v.Get(key)
+	val, ok = v.peek(key)
 
 	return
 }
 
 func (v *_cache[K, V]) Extract(key K) (V, bool) {
+	defer v.dispatch()
+
 	v.mux.Lock()
 	defer v.mux.Unlock()
 
@@ -84,72 +199,248 @@ func (v *_cache[K, V]) Extract(key K) (V, bool) {
 		return zeroValue, false
 	}
 
-	delete(v.list, key)
+	v.remove(key, EvictDeleted)
+
+	if item.expired(time.Now().UnixNano()) {
+		var zeroValue V
+		return zeroValue, false
+	}
 
-	return item, true
+	return item.val, true
 }
 
 func (v *_cache[K, V]) Set(key K, value V) {
+	v.SetWithTTL(key, value, v.defaultTTL)
+}
+
+func (v *_cache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+	defer v.dispatch()
+
+	v.mux.Lock()
+	defer v.mux.Unlock()
+
+	v.set(key, v.entry(value, ttl))
+}
+
+func (v *_cache[K, V]) SetWithCost(key K, value V, cost int64) {
+	defer v.dispatch()
+
 	v.mux.Lock()
 	defer v.mux.Unlock()
 
-	v.list[key] = value
+	item := v.entry(value, v.defaultTTL)
+	item.cost = cost
+	v.set(key, item)
+}
+
+func (v *_cache[K, V]) Cost() int64 {
+	v.mux.RLock()
+	defer v.mux.RUnlock()
+
+	return v.cost
+}
+
+// entry makes the element which expires after ttl
+func (v *_cache[K, V]) entry(value V, ttl time.Duration) entry[V] {
+	item := entry[V]{val: value, exp: expiration(ttl), cost: 1}
+	if v.costFn != nil {
+		item.cost = v.costFn(value)
+	}
+	if v.refresher != nil {
+		item.soft = expiration(v.softTTL)
+	}
+
+	return item
+}
+
+// set stores the element under the write lock
+func (v *_cache[K, V]) set(key K, item entry[V]) {
+	if v.limitCost > 0 && item.cost > v.limitCost {
+		v.remove(key, EvictReplaced)
+		v.notify(key, item, EvictCapacity)
+		return
+	}
+
+	if old, ok := v.list[key]; ok {
+		v.cost -= old.cost
+		v.notify(key, old, EvictReplaced)
+		if v.policy != nil {
+			v.policy.touch(key)
+		}
+	} else if v.policy != nil {
+		v.policy.add(key)
+	}
+
+	v.list[key] = item
+	v.cost += item.cost
+	delete(v.errs, key)
+	v.evict()
+}
+
+func (v *_cache[K, V]) TTL(key K) (time.Duration, bool) {
+	v.mux.RLock()
+	defer v.mux.RUnlock()
+
+	now := time.Now().UnixNano()
+
+	item, ok := v.list[key]
+	if !ok || item.expired(now) {
+		return 0, false
+	}
+
+	if item.exp == 0 {
+		return 0, true
+	}
+
+	return time.Duration(item.exp - now), true
+}
+
+func (v *_cache[K, V]) Touch(key K, ttl time.Duration) bool {
+	v.mux.Lock()
+	defer v.mux.Unlock()
+
+	item, ok := v.list[key]
+	if !ok || item.expired(time.Now().UnixNano()) {
+		return false
+	}
+
+	next := v.entry(item.val, ttl)
+	next.cost = item.cost
+	v.list[key] = next
+
+	return true
 }
 
 func (v *_cache[K, V]) Replace(data map[K]V) {
+	defer v.dispatch()
+
 	v.mux.Lock()
 	defer v.mux.Unlock()
 
-	v.list = data
+	for key, item := range v.list {
+		v.notify(key, item, EvictReplaced)
+	}
+
+	v.list = make(map[K]entry[V], len(data))
+	v.cost = 0
+	clear(v.errs)
+	for key, value := range data {
+		item := v.entry(value, v.defaultTTL)
+		v.list[key] = item
+		v.cost += item.cost
+	}
+
+	if v.policy != nil {
+		v.policy.reset()
+		for key := range v.list {
+			v.policy.add(key)
+		}
+		v.evict()
+	}
 }
 
 func (v *_cache[K, V]) Del(key K) {
+	v.del(key, EvictDeleted)
+}
+
+func (v *_cache[K, V]) del(key K, reason EvictReason) {
+	defer v.dispatch()
+
 	v.mux.Lock()
 	defer v.mux.Unlock()
 
+	v.remove(key, reason)
+	delete(v.errs, key)
+}
+
+// remove deletes the element under the write lock
+func (v *_cache[K, V]) remove(key K, reason EvictReason) {
+	item, ok := v.list[key]
+	if !ok {
+		return
+	}
+
 	delete(v.list, key)
+	v.cost -= item.cost
+	v.notify(key, item, reason)
+
+	if v.policy != nil {
+		v.policy.remove(key)
+	}
+}
+
+// evict removes elements over the capacity under the write lock
+func (v *_cache[K, V]) evict() {
+	if v.policy == nil {
+		return
+	}
+
+	// the last element is kept, it can be over the limit of the shard
+	// but not over the limit of the whole cache
+	for (v.maxCount > 0 && len(v.list) > v.maxCount) || (v.maxCost > 0 && v.cost > v.maxCost && len(v.list) > 1) {
+		key, ok := v.policy.victim()
+		if !ok {
+			return
+		}
+		v.remove(key, EvictCapacity)
+	}
 }
 
 func (v *_cache[K, V]) Keys() []K {
-	return v._keys(v.Size())
+	return v._keys(0)
 }
 
+// _keys returns not expired keys, all of them if limit <= 0
 func (v *_cache[K, V]) _keys(limit int) []K {
 	v.mux.RLock()
 	defer v.mux.RUnlock()
 
+	now := time.Now().UnixNano()
+
+	if limit <= 0 || limit > len(v.list) {
+		limit = len(v.list)
+	}
+
 	i := 0
 	result := make([]K, 0, limit)
-	for k := range v.list {
-		result = append(result, k)
-		i++
+	for k, item := range v.list {
 		if i >= limit {
 			break
 		}
+		if item.expired(now) {
+			continue
+		}
+		result = append(result, k)
+		i++
 	}
 
 	return result
 }
 
 func (v *_cache[K, V]) Flush() {
+	defer v.dispatch()
+
 	v.mux.Lock()
 	defer v.mux.Unlock()
 
-	for k := range v.list {
+	for k, item := range v.list {
 		delete(v.list, k)
+		v.notify(k, item, EvictFlushed)
 	}
-}
+	v.cost = 0
+	clear(v.errs)
 
-func (v *_cache[K, V]) Yield(limit int) iter.Seq2[K, V] {
-	if limit < 1 {
-		limit = v.Size()
+	if v.policy != nil {
+		v.policy.reset()
 	}
+}
 
+func (v *_cache[K, V]) Yield(limit int) iter.Seq2[K, V] {
 	keys := v._keys(limit)
 
 	return func(yield func(K, V) bool) {
 		for _, key := range keys {
-			if val, ok := v.Get(key); ok {
+			if val, ok := v.peek(key); ok {
 				if !yield(key, val) {
 					return
 				}
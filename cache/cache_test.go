@@ -165,3 +165,93 @@ func Benchmark_One(b *testing.B) {
 		}
 	})
 }
+
+func TestUnit_OptLRU(t *testing.T) {
+	c := cache.New[int, int](
+		cache.OptLRU[int, int](3),
+	)
+
+	for i := 0; i < 3; i++ {
+		c.Set(i, i)
+	}
+
+	_, ok := c.Get(0)
+	casecheck.True(t, ok)
+	casecheck.True(t, c.Has(1))
+
+	c.Set(3, 3)
+	casecheck.Equal(t, 3, c.Size())
+	casecheck.False(t, c.Has(1))
+	casecheck.True(t, c.Has(0))
+
+	c.Set(2, 20)
+	c.Set(4, 4)
+	casecheck.False(t, c.Has(0))
+	casecheck.True(t, c.Has(2))
+	casecheck.True(t, c.Has(3))
+
+	c.Del(3)
+	c.Set(5, 5)
+	c.Set(6, 6)
+	casecheck.Equal(t, 3, c.Size())
+	casecheck.False(t, c.Has(2))
+
+	c.Replace(map[int]int{1: 1, 2: 2, 3: 3, 4: 4})
+	casecheck.Equal(t, 3, c.Size())
+
+	c.Flush()
+	casecheck.Equal(t, 0, c.Size())
+	c.Set(7, 7)
+	casecheck.Equal(t, []int{7}, c.Keys())
+}
+
+func TestUnit_TTL(t *testing.T) {
+	c := cache.New[string, string]()
+
+	c.SetWithTTL("foo", "bar", 100*time.Millisecond)
+	c.Set("baz", "qux")
+
+	ttl, ok := c.TTL("foo")
+	casecheck.True(t, ok)
+	casecheck.True(t, ttl > 0 && ttl <= 100*time.Millisecond)
+
+	ttl, ok = c.TTL("baz")
+	casecheck.True(t, ok)
+	casecheck.Equal(t, time.Duration(0), ttl)
+
+	_, ok = c.TTL("none")
+	casecheck.False(t, ok)
+
+	casecheck.True(t, c.Touch("baz", 100*time.Millisecond))
+	casecheck.False(t, c.Touch("none", time.Second))
+
+	time.Sleep(150 * time.Millisecond)
+
+	_, ok = c.Get("foo")
+	casecheck.False(t, ok)
+	casecheck.False(t, c.Has("baz"))
+	_, ok = c.Extract("baz")
+	casecheck.False(t, ok)
+	casecheck.Equal(t, []string{}, c.Keys())
+	casecheck.False(t, c.Touch("foo", time.Second))
+}
+
+func TestUnit_OptDefaultTTL(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	c := cache.New[string, string](
+		cache.OptDefaultTTL[string, string](100*time.Millisecond),
+		cache.OptTTLClean[string, string](ctx, 50*time.Millisecond),
+	)
+
+	c.Set("foo", "bar")
+	c.SetWithTTL("baz", "qux", 0)
+	casecheck.True(t, c.Has("foo"))
+
+	time.Sleep(300 * time.Millisecond)
+
+	casecheck.False(t, c.Has("foo"))
+	casecheck.True(t, c.Has("baz"))
+	casecheck.Equal(t, 1, c.Size())
+}
@@ -0,0 +1,127 @@
+/*
+ *  Copyright (c) 2024-2025 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package cache
+
+import "time"
+
+type action uint8
+
+const (
+	actionKeep action = iota
+	actionStore
+	actionDelete
+)
+
+type updater[K comparable, V any] interface {
+	update(key K, call func(old V, ok bool) (V, action)) (V, bool)
+}
+
+func (v *_cache[K, V]) Compute(key K, call func(old V, ok bool) (V, bool)) (V, bool) {
+	return v.update(key, func(old V, ok bool) (V, action) {
+		val, keep := call(old, ok)
+		if !keep {
+			return val, actionDelete
+		}
+		return val, actionStore
+	})
+}
+
+func (v *_cache[K, V]) GetOrSet(key K, value V) (V, bool) {
+	loaded := false
+	val, _ := v.update(key, func(old V, ok bool) (V, action) {
+		if ok {
+			loaded = true
+			return old, actionKeep
+		}
+		return value, actionStore
+	})
+
+	return val, loaded
+}
+
+func (v *_cache[K, V]) SetIfAbsent(key K, value V) bool {
+	_, loaded := v.GetOrSet(key, value)
+	return !loaded
+}
+
+// update calls the handler under the write lock, the stored element keeps
+// the expiration time of the replaced one
+func (v *_cache[K, V]) update(key K, call func(old V, ok bool) (V, action)) (V, bool) {
+	defer v.dispatch()
+
+	v.mux.Lock()
+	defer v.mux.Unlock()
+
+	old, ok := v.list[key]
+	if ok && old.expired(time.Now().UnixNano()) {
+		ok = false
+	}
+
+	val, act := call(old.val, ok)
+
+	switch act {
+	case actionStore:
+		item := v.entry(val, v.defaultTTL)
+		if ok {
+			item.exp = old.exp
+		}
+		v.set(key, item)
+		_, ok = v.list[key]
+		return val, ok
+	case actionDelete:
+		v.remove(key, EvictDeleted)
+		var zeroValue V
+		return zeroValue, false
+	default:
+		if ok && v.policy != nil {
+			v.policy.touch(key)
+		}
+		return old.val, ok
+	}
+}
+
+// CompareAndSwap stores the value if the current one is equal to old
+func CompareAndSwap[K comparable, V comparable](c Cache[K, V], key K, old, value V) bool {
+	swapped := false
+	compare(c, key, func(cur V, ok bool) (V, action) {
+		if !ok || cur != old {
+			return cur, actionKeep
+		}
+		swapped = true
+		return value, actionStore
+	})
+
+	return swapped
+}
+
+// CompareAndDelete deletes the element if the current value is equal to old
+func CompareAndDelete[K comparable, V comparable](c Cache[K, V], key K, old V) bool {
+	deleted := false
+	compare(c, key, func(cur V, ok bool) (V, action) {
+		if !ok || cur != old {
+			return cur, actionKeep
+		}
+		deleted = true
+		return cur, actionDelete
+	})
+
+	return deleted
+}
+
+func compare[K comparable, V any](c Cache[K, V], key K, call func(old V, ok bool) (V, action)) {
+	if u, ok := c.(updater[K, V]); ok {
+		u.update(key, call)
+		return
+	}
+
+	c.Compute(key, func(old V, ok bool) (V, bool) {
+		val, act := call(old, ok)
+		if act == actionKeep {
+			return old, ok
+		}
+		return val, act == actionStore
+	})
+}
@@ -0,0 +1,108 @@
+/*
+ *  Copyright (c) 2024-2025 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package cache_test
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"go.osspkg.com/casecheck"
+
+	"go.osspkg.com/ioutils/cache"
+)
+
+func TestUnit_Compute(t *testing.T) {
+	c := cache.New[string, int]()
+
+	val, ok := c.Compute("foo", func(old int, ok bool) (int, bool) {
+		casecheck.False(t, ok)
+		return old + 1, true
+	})
+	casecheck.True(t, ok)
+	casecheck.Equal(t, 1, val)
+
+	_, ok = c.Compute("foo", func(old int, ok bool) (int, bool) {
+		casecheck.True(t, ok)
+		return 0, false
+	})
+	casecheck.False(t, ok)
+	casecheck.False(t, c.Has("foo"))
+
+	val, loaded := c.GetOrSet("foo", 2)
+	casecheck.False(t, loaded)
+	casecheck.Equal(t, 2, val)
+	val, loaded = c.GetOrSet("foo", 3)
+	casecheck.True(t, loaded)
+	casecheck.Equal(t, 2, val)
+
+	casecheck.False(t, c.SetIfAbsent("foo", 4))
+	casecheck.True(t, c.SetIfAbsent("bar", 4))
+
+	casecheck.False(t, cache.CompareAndSwap(c, "foo", 3, 5))
+	casecheck.True(t, cache.CompareAndSwap(c, "foo", 2, 5))
+	casecheck.False(t, cache.CompareAndSwap(c, "none", 0, 5))
+	val, _ = c.Get("foo")
+	casecheck.Equal(t, 5, val)
+
+	casecheck.False(t, cache.CompareAndDelete(c, "foo", 2))
+	casecheck.True(t, cache.CompareAndDelete(c, "foo", 5))
+	casecheck.False(t, c.Has("foo"))
+}
+
+func TestUnit_ComputeRace(t *testing.T) {
+	for name, c := range map[string]cache.Cache[string, int]{
+		"single":  cache.New[string, int](),
+		"sharded": cache.NewSharded[string, int](4, nil),
+	} {
+		t.Run(name, func(t *testing.T) {
+			var (
+				wg      sync.WaitGroup
+				sets    atomic.Int32
+				swaps   atomic.Int32
+				deletes atomic.Int32
+			)
+
+			for i := 0; i < 16; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for j := 0; j < 100; j++ {
+						c.Compute("counter", func(old int, _ bool) (int, bool) {
+							return old + 1, true
+						})
+
+						if c.SetIfAbsent("once", j) {
+							sets.Add(1)
+						}
+
+						for {
+							cur, _ := c.GetOrSet("cas", 0)
+							if cache.CompareAndSwap(c, "cas", cur, cur+1) {
+								swaps.Add(1)
+								break
+							}
+						}
+
+						c.Set("del", j)
+						if cache.CompareAndDelete(c, "del", j) {
+							deletes.Add(1)
+						}
+					}
+				}()
+			}
+			wg.Wait()
+
+			val, _ := c.Get("counter")
+			casecheck.Equal(t, 1600, val)
+			casecheck.Equal(t, int32(1), sets.Load())
+			val, _ = c.Get("cas")
+			casecheck.Equal(t, 1600, val)
+			casecheck.Equal(t, int32(1600), swaps.Load())
+			casecheck.True(t, deletes.Load() > 0)
+		})
+	}
+}
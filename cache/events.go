@@ -0,0 +1,71 @@
+/*
+ *  Copyright (c) 2024-2025 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package cache
+
+import "time"
+
+type EvictReason uint8
+
+const (
+	EvictDeleted EvictReason = iota
+	EvictExpired
+	EvictCapacity
+	EvictReplaced
+	EvictFlushed
+)
+
+func (v EvictReason) String() string {
+	switch v {
+	case EvictDeleted:
+		return "deleted"
+	case EvictExpired:
+		return "expired"
+	case EvictCapacity:
+		return "capacity"
+	case EvictReplaced:
+		return "replaced"
+	case EvictFlushed:
+		return "flushed"
+	default:
+		return "unknown"
+	}
+}
+
+type event[K comparable, V any] struct {
+	key    K
+	val    V
+	reason EvictReason
+}
+
+// notify queues the event under the write lock, an expired element is
+// reported as expired whatever the reason of removal
+func (v *_cache[K, V]) notify(key K, item entry[V], reason EvictReason) {
+	if v.onEvict == nil {
+		return
+	}
+
+	if reason != EvictFlushed && item.expired(time.Now().UnixNano()) {
+		reason = EvictExpired
+	}
+
+	v.events = append(v.events, event[K, V]{key: key, val: item.val, reason: reason})
+}
+
+// dispatch calls the handler for queued events outside the lock
+func (v *_cache[K, V]) dispatch() {
+	if v.onEvict == nil {
+		return
+	}
+
+	v.mux.Lock()
+	events := v.events
+	v.events = nil
+	v.mux.Unlock()
+
+	for _, e := range events {
+		v.onEvict(e.key, e.val, e.reason)
+	}
+}
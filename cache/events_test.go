@@ -0,0 +1,62 @@
+/*
+ *  Copyright (c) 2024-2025 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package cache_test
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"go.osspkg.com/casecheck"
+
+	"go.osspkg.com/ioutils/cache"
+)
+
+func TestUnit_OptOnEvict(t *testing.T) {
+	var (
+		c      cache.Cache[string, int]
+		events []string
+	)
+
+	c = cache.New[string, int](
+		cache.OptLRU[string, int](2),
+		cache.OptOnEvict[string, int](func(key string, value int, reason cache.EvictReason) {
+			// the handler is called outside the lock
+			casecheck.True(t, c.Size() >= 0)
+			events = append(events, fmt.Sprintf("%s=%d:%s", key, value, reason))
+		}),
+	)
+
+	c.Set("a", 1)
+	c.Set("a", 2)
+	c.Set("b", 3)
+	c.Set("c", 4)
+	c.Del("b")
+	c.Del("none")
+	c.Extract("c")
+	c.SetWithTTL("d", 5, time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	c.Del("d")
+
+	casecheck.Equal(t, []string{
+		"a=1:replaced",
+		"a=2:capacity",
+		"b=3:deleted",
+		"c=4:deleted",
+		"d=5:expired",
+	}, events)
+
+	events = events[:0]
+	c.Replace(map[string]int{"x": 1, "y": 2})
+	c.Flush()
+	sort.Strings(events)
+
+	casecheck.Equal(t, []string{
+		"x=1:flushed",
+		"y=2:flushed",
+	}, events)
+}
@@ -0,0 +1,257 @@
+/*
+ *  Copyright (c) 2024-2025 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrNotFound is returned for keys which are missing in the result of a bulk loader
+	ErrNotFound = errors.New("not found")
+	// ErrLoaderPanic is returned to all waiters of a loader which panicked
+	ErrLoaderPanic = errors.New("loader panic")
+)
+
+type (
+	// Loader loads the value of a missing key
+	Loader[K comparable, V any] func(ctx context.Context, key K) (V, error)
+	// BulkLoader loads the values of missing keys, keys omitted in the result are not found
+	BulkLoader[K comparable, V any] func(ctx context.Context, keys []K) (map[K]V, error)
+
+	call[V any] struct {
+		done chan struct{}
+		val  V
+		err  error
+	}
+
+	failure struct {
+		err error
+		exp int64
+	}
+)
+
+func (v *_cache[K, V]) GetOrLoad(ctx context.Context, key K, loader Loader[K, V]) (V, error) {
+	if val, ok := v.Get(key); ok {
+		return val, nil
+	}
+
+	calls := v.start(ctx, []K{key}, func(ctx context.Context, keys []K) (map[K]V, error) {
+		val, err := loader(ctx, keys[0])
+		if err != nil {
+			return nil, err
+		}
+		return map[K]V{keys[0]: val}, nil
+	})
+
+	return calls[key].wait(ctx)
+}
+
+func (v *_cache[K, V]) GetOrLoadAll(ctx context.Context, keys []K, loader BulkLoader[K, V]) (map[K]V, error) {
+	result := make(map[K]V, len(keys))
+	missing := make([]K, 0, len(keys))
+
+	for _, key := range keys {
+		if val, ok := v.Get(key); ok {
+			result[key] = val
+			continue
+		}
+		missing = append(missing, key)
+	}
+
+	if len(missing) == 0 {
+		return result, nil
+	}
+
+	return result, collect(ctx, v.start(ctx, missing, loader), result)
+}
+
+// collect waits for the calls and puts the found values to the result
+func collect[K comparable, V any](ctx context.Context, calls map[K]*call[V], result map[K]V) error {
+	for key, c := range calls {
+		val, err := c.wait(ctx)
+		switch {
+		case errors.Is(err, ErrNotFound):
+		case err != nil:
+			return err
+		default:
+			result[key] = val
+		}
+	}
+
+	return nil
+}
+
+// start runs the loader for keys which are not loading yet, the loader does not
+// depend on the cancellation of the context because the result is shared by waiters
+func (v *_cache[K, V]) start(ctx context.Context, keys []K, loader BulkLoader[K, V]) map[K]*call[V] {
+	calls, own := v.reserve(keys)
+
+	if len(own) > 0 {
+		go v.load(context.WithoutCancel(ctx), own, calls, loader)
+	}
+
+	return calls
+}
+
+// reserve returns the calls of keys and the keys which the caller must load
+// and pass to finish
+func (v *_cache[K, V]) reserve(keys []K) (map[K]*call[V], []K) {
+	v.loadMux.Lock()
+	defer v.loadMux.Unlock()
+
+	calls := make(map[K]*call[V], len(keys))
+	own := make([]K, 0, len(keys))
+	for _, key := range keys {
+		if _, ok := calls[key]; ok {
+			continue
+		}
+		if c, ok := v.calls[key]; ok {
+			calls[key] = c
+			continue
+		}
+		c := &call[V]{done: make(chan struct{})}
+		if v.loaded(key, c) {
+			close(c.done)
+			calls[key] = c
+			continue
+		}
+		v.calls[key] = c
+		calls[key] = c
+		own = append(own, key)
+	}
+
+	return calls, own
+}
+
+// loaded fills the call with the value or the loader error stored while the key was
+// not locked for loading
+func (v *_cache[K, V]) loaded(key K, c *call[V]) bool {
+	v.mux.RLock()
+	defer v.mux.RUnlock()
+
+	now := time.Now().UnixNano()
+
+	if item, ok := v.list[key]; ok && !item.expired(now) {
+		c.val = item.val
+		return true
+	}
+
+	if f, ok := v.errs[key]; ok && f.exp > now {
+		c.err = f.err
+		return true
+	}
+
+	return false
+}
+
+func (v *_cache[K, V]) load(ctx context.Context, keys []K, calls map[K]*call[V], loader BulkLoader[K, V]) {
+	values, err := recovered(loader)(ctx, keys)
+	v.finish(keys, calls, values, err)
+}
+
+// finish stores the loader result of the reserved keys and releases the waiters
+func (v *_cache[K, V]) finish(keys []K, calls map[K]*call[V], values map[K]V, err error) {
+	defer func() {
+		v.loadMux.Lock()
+		for _, key := range keys {
+			delete(v.calls, key)
+		}
+		v.loadMux.Unlock()
+
+		for _, key := range keys {
+			close(calls[key].done)
+		}
+	}()
+
+	v.store(keys, calls, values, err)
+	v.dispatch()
+}
+
+// store saves the loader result to the cache and the calls
+func (v *_cache[K, V]) store(keys []K, calls map[K]*call[V], values map[K]V, err error) {
+	v.mux.Lock()
+	defer v.mux.Unlock()
+
+	for _, key := range keys {
+		c := calls[key]
+		val, ok := values[key]
+		switch {
+		case err != nil:
+			c.err = err
+		case !ok:
+			c.err = ErrNotFound
+		default:
+			c.val = val
+			v.set(key, v.entry(val, v.defaultTTL))
+			continue
+		}
+		// the stale element is retried after the soft ttl, not on every Get
+		if item, ok := v.list[key]; ok && item.soft > 0 {
+			item.soft = expiration(v.softTTL)
+			v.list[key] = item
+		}
+		if v.errorTTL > 0 {
+			if v.errs == nil {
+				v.errs = make(map[K]failure)
+			}
+			v.errs[key] = failure{err: c.err, exp: expiration(v.errorTTL)}
+		}
+	}
+}
+
+// recovered converts a panic of the loader to ErrLoaderPanic
+func recovered[K comparable, V any](loader BulkLoader[K, V]) BulkLoader[K, V] {
+	return func(ctx context.Context, keys []K) (values map[K]V, err error) {
+		defer func() {
+			if e := recover(); e != nil {
+				values, err = nil, fmt.Errorf("%w: %v", ErrLoaderPanic, e)
+			}
+		}()
+
+		return loader(ctx, keys)
+	}
+}
+
+// refresh reloads the stale element in background, the stale value is kept on error
+func (v *_cache[K, V]) refresh(key K) {
+	v.loadMux.Lock()
+	defer v.loadMux.Unlock()
+
+	if _, ok := v.calls[key]; ok {
+		return
+	}
+
+	c := &call[V]{done: make(chan struct{})}
+	v.calls[key] = c
+
+	go v.load(v.refreshCtx, []K{key}, map[K]*call[V]{key: c}, func(ctx context.Context, keys []K) (map[K]V, error) {
+		values, err := recovered(func(ctx context.Context, keys []K) (map[K]V, error) {
+			val, err := v.refresher(ctx, keys[0])
+			if err != nil {
+				return nil, err
+			}
+			return map[K]V{keys[0]: val}, nil
+		})(ctx, keys)
+		if err != nil && v.onRefreshErr != nil {
+			v.onRefreshErr(keys[0], err)
+		}
+		return values, err
+	})
+}
+
+func (c *call[V]) wait(ctx context.Context) (V, error) {
+	select {
+	case <-c.done:
+		return c.val, c.err
+	case <-ctx.Done():
+		var zeroValue V
+		return zeroValue, ctx.Err()
+	}
+}
@@ -0,0 +1,247 @@
+/*
+ *  Copyright (c) 2024-2025 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.osspkg.com/casecheck"
+
+	"go.osspkg.com/ioutils/cache"
+)
+
+func TestUnit_GetOrLoad(t *testing.T) {
+	c := cache.New[string, string]()
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	loader := func(_ context.Context, key string) (string, error) {
+		calls.Add(1)
+		<-release
+		return "val:" + key, nil
+	}
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			val, err := c.GetOrLoad(context.TODO(), "foo", loader)
+			casecheck.NoError(t, err)
+			casecheck.Equal(t, "val:foo", val)
+		}()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := c.GetOrLoad(ctx, "foo", loader)
+	casecheck.True(t, errors.Is(err, context.DeadlineExceeded))
+
+	close(release)
+	wg.Wait()
+
+	casecheck.Equal(t, int32(1), calls.Load())
+	val, ok := c.Get("foo")
+	casecheck.True(t, ok)
+	casecheck.Equal(t, "val:foo", val)
+
+	val, err = c.GetOrLoad(context.TODO(), "foo", loader)
+	casecheck.NoError(t, err)
+	casecheck.Equal(t, "val:foo", val)
+	casecheck.Equal(t, int32(1), calls.Load())
+}
+
+func TestUnit_OptErrorTTL(t *testing.T) {
+	c := cache.New[string, string](cache.OptErrorTTL[string, string](100 * time.Millisecond))
+
+	var calls atomic.Int32
+	loader := func(_ context.Context, key string) (string, error) {
+		if calls.Add(1) == 1 {
+			return "", fmt.Errorf("fail")
+		}
+		return key, nil
+	}
+
+	_, err := c.GetOrLoad(context.TODO(), "foo", loader)
+	casecheck.Error(t, err)
+	_, err = c.GetOrLoad(context.TODO(), "foo", loader)
+	casecheck.Error(t, err)
+	casecheck.Equal(t, int32(1), calls.Load())
+	casecheck.False(t, c.Has("foo"))
+
+	time.Sleep(150 * time.Millisecond)
+
+	val, err := c.GetOrLoad(context.TODO(), "foo", loader)
+	casecheck.NoError(t, err)
+	casecheck.Equal(t, "foo", val)
+	casecheck.Equal(t, int32(2), calls.Load())
+}
+
+func TestUnit_GetOrLoadPanic(t *testing.T) {
+	c := cache.New[string, string]()
+
+	_, err := c.GetOrLoad(context.TODO(), "foo", func(context.Context, string) (string, error) {
+		panic("boom")
+	})
+	casecheck.True(t, errors.Is(err, cache.ErrLoaderPanic))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	val, err := c.GetOrLoad(ctx, "foo", func(_ context.Context, key string) (string, error) {
+		return key, nil
+	})
+	casecheck.NoError(t, err)
+	casecheck.Equal(t, "foo", val)
+}
+
+func TestUnit_GetOrLoadAll(t *testing.T) {
+	c := cache.New[int, string]()
+	c.Set(1, "cached")
+
+	var loaded [][]int
+	loader := func(_ context.Context, keys []int) (map[int]string, error) {
+		loaded = append(loaded, keys)
+		result := make(map[int]string, len(keys))
+		for _, key := range keys {
+			if key%2 == 0 {
+				result[key] = fmt.Sprintf("val:%d", key)
+			}
+		}
+		return result, nil
+	}
+
+	result, err := c.GetOrLoadAll(context.TODO(), []int{1, 2, 3, 4, 2}, loader)
+	casecheck.NoError(t, err)
+	casecheck.Equal(t, map[int]string{1: "cached", 2: "val:2", 4: "val:4"}, result)
+	casecheck.Equal(t, 1, len(loaded))
+	casecheck.Equal(t, 3, len(loaded[0]))
+
+	_, err = c.GetOrLoad(context.TODO(), 3, func(_ context.Context, key int) (string, error) {
+		return "", cache.ErrNotFound
+	})
+	casecheck.True(t, errors.Is(err, cache.ErrNotFound))
+
+	result, err = c.GetOrLoadAll(context.TODO(), []int{2, 4}, loader)
+	casecheck.NoError(t, err)
+	casecheck.Equal(t, 2, len(result))
+	casecheck.Equal(t, 1, len(loaded))
+
+	_, err = c.GetOrLoadAll(context.TODO(), []int{5}, func(context.Context, []int) (map[int]string, error) {
+		return nil, fmt.Errorf("fail")
+	})
+	casecheck.Error(t, err)
+}
+
+func eventually(t *testing.T, cond func() bool) {
+	t.Helper()
+	for i := 0; i < 200; i++ {
+		if cond() {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Errorf("condition is not met")
+}
+
+func TestUnit_OptStaleWhileRevalidate(t *testing.T) {
+	var (
+		calls   atomic.Int32
+		fail    atomic.Bool
+		release = make(chan struct{})
+		errs    = make(chan string, 10)
+	)
+
+	c := cache.New[string, int](
+		cache.OptStaleWhileRevalidate[string, int](context.TODO(), 50*time.Millisecond, 200*time.Millisecond,
+			func(_ context.Context, key string) (int, error) {
+				if fail.Load() {
+					return 0, fmt.Errorf("fail")
+				}
+				<-release
+				return int(calls.Add(1)), nil
+			}),
+		cache.OptOnRefreshError[string, int](func(key string, err error) {
+			errs <- key
+		}),
+	)
+
+	c.Set("foo", 0)
+	val, ok := c.Get("foo")
+	casecheck.True(t, ok)
+	casecheck.Equal(t, 0, val)
+
+	time.Sleep(70 * time.Millisecond)
+
+	for i := 0; i < 5; i++ {
+		val, ok = c.Get("foo")
+		casecheck.True(t, ok)
+		casecheck.Equal(t, 0, val)
+	}
+	close(release)
+
+	eventually(t, func() bool {
+		val, _ = c.Get("foo")
+		return val == 1
+	})
+	casecheck.Equal(t, int32(1), calls.Load())
+
+	fail.Store(true)
+	time.Sleep(70 * time.Millisecond)
+
+	val, ok = c.Get("foo")
+	casecheck.True(t, ok)
+	casecheck.Equal(t, 1, val)
+	casecheck.Equal(t, "foo", <-errs)
+
+	val, ok = c.Get("foo")
+	casecheck.True(t, ok)
+	casecheck.Equal(t, 1, val)
+
+	eventually(t, func() bool {
+		return !c.Has("foo")
+	})
+}
+
+func TestUnit_OptStaleWhileRevalidateError(t *testing.T) {
+	var calls atomic.Int32
+	errs := make(chan string, 10)
+
+	c := cache.New[string, int](
+		cache.OptStaleWhileRevalidate[string, int](context.TODO(), 50*time.Millisecond, time.Hour,
+			func(_ context.Context, key string) (int, error) {
+				calls.Add(1)
+				return 0, fmt.Errorf("fail")
+			}),
+		cache.OptOnRefreshError[string, int](func(key string, err error) {
+			errs <- key
+		}),
+	)
+
+	c.Set("foo", 1)
+	time.Sleep(70 * time.Millisecond)
+
+	c.Get("foo")
+	casecheck.Equal(t, "foo", <-errs)
+
+	for i := 0; i < 1000; i++ {
+		val, ok := c.Get("foo")
+		casecheck.True(t, ok)
+		casecheck.Equal(t, 1, val)
+	}
+	casecheck.Equal(t, int32(1), calls.Load())
+
+	time.Sleep(70 * time.Millisecond)
+	c.Get("foo")
+	casecheck.Equal(t, "foo", <-errs)
+	casecheck.Equal(t, int32(2), calls.Load())
+}
@@ -22,19 +22,21 @@ func OptTimeClean[K comparable, V Timestamp](ctx context.Context, interval time.
 				func(ctx context.Context, t time.Time) {
 					curr := t.Unix()
 
+					defer v.dispatch()
+
 					v.mux.Lock()
 					defer v.mux.Unlock()
 
-					for key, value := range v.list {
-						if value.Timestamp() < curr {
-							delete(v.list, key)
+					for key, item := range v.list {
+						if item.val.Timestamp() < curr {
+							v.remove(key, EvictExpired)
 						}
 					}
 				},
 			},
 		}
 
-		tik.Run(ctx)
+		go tik.Run(ctx)
 	}
 }
 
@@ -51,18 +53,184 @@ func OptCountRandomClean[K comparable, V any](ctx context.Context, maxCount int,
 			Calls: []routine.TickFunc{
 				func(ctx context.Context, _ time.Time) {
 
-					removeCount := v.Size() - maxCount
+					removeCount := v.Size() - v.share(maxCount)
 					if removeCount <= 0 {
 						return
 					}
 
 					for key := range v.Yield(removeCount) {
-						v.Del(key)
+						v.del(key, EvictCapacity)
+					}
+				},
+			},
+		}
+
+		go tik.Run(ctx)
+	}
+}
+
+// OptLRU limits the cache to capacity elements and evicts the least recently
+// used element on Set. Get updates the recency, Has does not. With NewSharded
+// the capacity is divided between shards, each shard evicts by its own part,
+// so the cache may keep less than capacity elements; the capacity must be at
+// least 16 per shard.
+func OptLRU[K comparable, V any](capacity int) Option[K, V] {
+	return func(v *_cache[K, V]) {
+
+		if capacity <= 0 {
+			panic("OptLRU: capacity <= 0")
+		}
+		if v.parts > 1 && capacity < v.parts*minShare {
+			panic("OptLRU: capacity < shards * 16")
+		}
+
+		v.maxCount = v.share(capacity)
+		v.policy = newLRU[K](v.maxCount)
+	}
+}
+
+// OptTinyLFU limits the cache to capacity elements with the frequency-aware
+// W-TinyLFU eviction, which keeps popular elements under scans. With NewSharded
+// the capacity is divided as in OptLRU.
+func OptTinyLFU[K comparable, V any](capacity int) Option[K, V] {
+	return func(v *_cache[K, V]) {
+
+		if capacity <= 0 {
+			panic("OptTinyLFU: capacity <= 0")
+		}
+		if v.parts > 1 && capacity < v.parts*minShare {
+			panic("OptTinyLFU: capacity < shards * 16")
+		}
+
+		v.maxCount = v.share(capacity)
+		v.policy = newTinyLFU[K](v.maxCount)
+	}
+}
+
+// OptDefaultTTL sets the time to live of elements stored with Set and Replace.
+func OptDefaultTTL[K comparable, V any](ttl time.Duration) Option[K, V] {
+	return func(v *_cache[K, V]) {
+		v.defaultTTL = ttl
+	}
+}
+
+// OptTTLClean removes expired elements every interval. Expired elements are
+// never returned even without it, but they are kept in memory until removed.
+func OptTTLClean[K comparable, V any](ctx context.Context, interval time.Duration) Option[K, V] {
+	return func(v *_cache[K, V]) {
+
+		tik := routine.Ticker{
+			Interval: interval,
+			OnStart:  false,
+			Calls: []routine.TickFunc{
+				func(ctx context.Context, t time.Time) {
+					curr := t.UnixNano()
+
+					defer v.dispatch()
+
+					v.mux.Lock()
+					defer v.mux.Unlock()
+
+					for key, item := range v.list {
+						if item.expired(curr) {
+							v.remove(key, EvictExpired)
+						}
 					}
 				},
 			},
 		}
 
-		tik.Run(ctx)
+		go tik.Run(ctx)
+	}
+}
+
+// OptErrorTTL caches loader errors for ttl, so GetOrLoad returns the error
+// without calling the loader again.
+func OptErrorTTL[K comparable, V any](ttl time.Duration) Option[K, V] {
+	return func(v *_cache[K, V]) {
+		v.errorTTL = ttl
+	}
+}
+
+// OptStaleWhileRevalidate keeps elements for hard ttl. After soft ttl Get returns
+// the stale element and reloads it once in background by the loader. A failed
+// reload is retried after the next soft ttl.
+func OptStaleWhileRevalidate[K comparable, V any](ctx context.Context, soft, hard time.Duration, loader Loader[K, V]) Option[K, V] {
+	return func(v *_cache[K, V]) {
+
+		if soft <= 0 {
+			panic("OptStaleWhileRevalidate: soft <= 0")
+		}
+		if hard > 0 && hard <= soft {
+			panic("OptStaleWhileRevalidate: hard <= soft")
+		}
+
+		v.softTTL = soft
+		v.defaultTTL = hard
+		v.refreshCtx = ctx
+		v.refresher = loader
+	}
+}
+
+// OptOnRefreshError sets the handler of background refresh errors.
+func OptOnRefreshError[K comparable, V any](call func(key K, err error)) Option[K, V] {
+	return func(v *_cache[K, V]) {
+		v.onRefreshErr = call
+	}
+}
+
+// OptOnEvict sets the handler called for each element removed from the cache
+// or overwritten. It is called outside the cache lock.
+func OptOnEvict[K comparable, V any](call func(key K, value V, reason EvictReason)) Option[K, V] {
+	return func(v *_cache[K, V]) {
+		v.onEvict = call
+	}
+}
+
+// OptSnapshotCodec sets the format of Snapshot and Restore by the codec extension,
+// gob is used by default.
+func OptSnapshotCodec[K comparable, V any](ext string) Option[K, V] {
+	return func(v *_cache[K, V]) {
+		v.snapshot.ext = ext
+	}
+}
+
+// OptSnapshotFile restores the cache from the file on creation and writes the
+// snapshot to the file every interval and on the context done.
+func OptSnapshotFile[K comparable, V any](ctx context.Context, filename string, interval time.Duration, onError func(error)) Option[K, V] {
+	return func(v *_cache[K, V]) {
+
+		if interval <= 0 {
+			panic("OptSnapshotFile: interval <= 0")
+		}
+
+		v.snapshot.ctx = ctx
+		v.snapshot.filename = filename
+		v.snapshot.interval = interval
+		v.snapshot.onError = onError
+	}
+}
+
+// OptCost sets the cost of elements stored without explicit cost, 1 by default.
+func OptCost[K comparable, V any](call func(value V) int64) Option[K, V] {
+	return func(v *_cache[K, V]) {
+		v.costFn = call
+	}
+}
+
+// OptMaxCost limits the total cost of elements. Elements are evicted by the
+// eviction policy of the cache or LRU if it is not set, an element with cost
+// over the limit is not stored. With NewSharded the limit is divided between
+// shards, a shard keeps one element over its part, so the total cost may exceed
+// the limit by the cost of such elements.
+func OptMaxCost[K comparable, V any](maxCost int64) Option[K, V] {
+	return func(v *_cache[K, V]) {
+
+		if maxCost <= 0 {
+			panic("OptMaxCost: maxCost <= 0")
+		}
+
+		v.limitCost = maxCost
+		v.maxCost = (maxCost + int64(v.parts) - 1) / int64(v.parts)
 	}
 }
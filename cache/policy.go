@@ -0,0 +1,128 @@
+/*
+ *  Copyright (c) 2024-2025 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package cache
+
+// policy tracks keys of the cache and chooses which one to evict.
+// It is called under the write lock of the cache.
+type policy[K comparable] interface {
+	add(key K)
+	touch(key K)
+	remove(key K)
+	victim() (K, bool)
+	reset()
+}
+
+type (
+	node[K comparable] struct {
+		key        K
+		prev, next *node[K]
+		// segment of the policy holding the node
+		seg uint8
+	}
+
+	// list is an intrusive doubly linked list with a sentinel root
+	list[K comparable] struct {
+		root node[K]
+		size int
+	}
+)
+
+func newList[K comparable]() *list[K] {
+	l := &list[K]{}
+	l.root.next = &l.root
+	l.root.prev = &l.root
+	return l
+}
+
+func (l *list[K]) pushFront(n *node[K]) {
+	n.prev = &l.root
+	n.next = l.root.next
+	l.root.next.prev = n
+	l.root.next = n
+	l.size++
+}
+
+func (l *list[K]) unlink(n *node[K]) {
+	n.prev.next = n.next
+	n.next.prev = n.prev
+	n.prev, n.next = nil, nil
+	l.size--
+}
+
+func (l *list[K]) moveToFront(n *node[K]) {
+	l.unlink(n)
+	l.pushFront(n)
+}
+
+func (l *list[K]) front() *node[K] {
+	if l.size == 0 {
+		return nil
+	}
+	return l.root.next
+}
+
+func (l *list[K]) back() *node[K] {
+	if l.size == 0 {
+		return nil
+	}
+	return l.root.prev
+}
+
+func (l *list[K]) clear() {
+	l.root.next = &l.root
+	l.root.prev = &l.root
+	l.size = 0
+}
+
+type lru[K comparable] struct {
+	items map[K]*node[K]
+	order *list[K]
+}
+
+func newLRU[K comparable](capacity int) *lru[K] {
+	return &lru[K]{
+		items: make(map[K]*node[K], capacity+1),
+		order: newList[K](),
+	}
+}
+
+func (v *lru[K]) add(key K) {
+	if n, ok := v.items[key]; ok {
+		v.order.moveToFront(n)
+		return
+	}
+
+	n := &node[K]{key: key}
+	v.items[key] = n
+	v.order.pushFront(n)
+}
+
+func (v *lru[K]) touch(key K) {
+	if n, ok := v.items[key]; ok {
+		v.order.moveToFront(n)
+	}
+}
+
+func (v *lru[K]) remove(key K) {
+	if n, ok := v.items[key]; ok {
+		v.order.unlink(n)
+		delete(v.items, key)
+	}
+}
+
+func (v *lru[K]) victim() (K, bool) {
+	n := v.order.back()
+	if n == nil {
+		var zero K
+		return zero, false
+	}
+	return n.key, true
+}
+
+func (v *lru[K]) reset() {
+	clear(v.items)
+	v.order.clear()
+}
@@ -0,0 +1,184 @@
+/*
+ *  Copyright (c) 2024-2025 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package cache_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"go.osspkg.com/casecheck"
+
+	"go.osspkg.com/ioutils/cache"
+)
+
+func TestUnit_OptTinyLFU(t *testing.T) {
+	c := cache.New[int, int](
+		cache.OptTinyLFU[int, int](100),
+	)
+
+	for i := 0; i < 50; i++ {
+		c.Set(i, i)
+		for j := 0; j < 5; j++ {
+			c.Get(i)
+		}
+	}
+
+	for i := 1000; i < 2000; i++ {
+		c.Set(i, i)
+		casecheck.True(t, c.Size() <= 100)
+	}
+
+	hot := 0
+	for i := 0; i < 50; i++ {
+		if c.Has(i) {
+			hot++
+		}
+	}
+	casecheck.True(t, hot >= 45, hot)
+
+	c.Flush()
+	casecheck.Equal(t, 0, c.Size())
+	c.Set(1, 1)
+	casecheck.True(t, c.Has(1))
+
+	small := cache.New[int, int](cache.OptTinyLFU[int, int](1))
+	small.Set(1, 1)
+	small.Set(2, 2)
+	casecheck.Equal(t, 1, small.Size())
+}
+
+type trace func(r *rand.Rand, i int) int
+
+func zipfTrace(keys uint64) trace {
+	var z *rand.Zipf
+	return func(r *rand.Rand, _ int) int {
+		if z == nil {
+			z = rand.NewZipf(r, 1.1, 1, keys)
+		}
+		return int(z.Uint64())
+	}
+}
+
+// scanTrace mixes the zipf hot set with long sequential scans of cold keys.
+func scanTrace(keys uint64) trace {
+	zt := zipfTrace(keys)
+	return func(r *rand.Rand, i int) int {
+		if (i/1000)%2 == 1 {
+			return int(keys) + i
+		}
+		return zt(r, i)
+	}
+}
+
+// benchHits trims the cache to capacity after every Set by random eviction, so
+// a cache without a policy is compared with the same number of elements
+func benchHits(b *testing.B, c cache.Cache[int, int], capacity int, tr trace) {
+	r := rand.New(rand.NewSource(1))
+	hits := 0
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		key := tr(r, i)
+		if _, ok := c.Get(key); ok {
+			hits++
+			continue
+		}
+		c.Set(key, i)
+		for c.Size() > capacity {
+			k, _, _ := c.One()
+			c.Del(k)
+		}
+	}
+
+	b.ReportMetric(100*float64(hits)/float64(b.N), "hit%")
+}
+
+func benchPolicies(b *testing.B, tr func() trace) {
+	const capacity = 1000
+
+	b.Run("random", func(b *testing.B) {
+		benchHits(b, cache.New[int, int](), capacity, tr())
+	})
+	b.Run("lru", func(b *testing.B) {
+		benchHits(b, cache.New[int, int](cache.OptLRU[int, int](capacity)), capacity, tr())
+	})
+	b.Run("tinylfu", func(b *testing.B) {
+		benchHits(b, cache.New[int, int](cache.OptTinyLFU[int, int](capacity)), capacity, tr())
+	})
+}
+
+func Benchmark_PolicyZipf(b *testing.B) {
+	benchPolicies(b, func() trace { return zipfTrace(100000) })
+}
+
+func Benchmark_PolicyScan(b *testing.B) {
+	benchPolicies(b, func() trace { return scanTrace(100000) })
+}
+
+func TestUnit_OptMaxCost(t *testing.T) {
+	var evicted []string
+
+	c := cache.New[string, []byte](
+		cache.OptMaxCost[string, []byte](10),
+		cache.OptCost[string, []byte](func(value []byte) int64 { return int64(len(value)) }),
+		cache.OptOnEvict[string, []byte](func(key string, _ []byte, reason cache.EvictReason) {
+			evicted = append(evicted, key+":"+reason.String())
+		}),
+	)
+
+	c.Set("a", []byte("1234"))
+	c.Set("b", []byte("1234"))
+	casecheck.Equal(t, int64(8), c.Cost())
+
+	c.Get("a")
+	c.Set("c", []byte("123"))
+	casecheck.Equal(t, int64(7), c.Cost())
+	casecheck.True(t, c.Has("a"))
+	casecheck.False(t, c.Has("b"))
+
+	c.Set("a", []byte("1"))
+	casecheck.Equal(t, int64(4), c.Cost())
+
+	c.SetWithCost("d", nil, 6)
+	casecheck.Equal(t, int64(10), c.Cost())
+
+	c.Set("e", []byte("12345678901"))
+	casecheck.False(t, c.Has("e"))
+	casecheck.Equal(t, int64(10), c.Cost())
+
+	c.Del("d")
+	casecheck.Equal(t, int64(4), c.Cost())
+
+	casecheck.Equal(t, []string{"b:capacity", "a:replaced", "e:capacity", "d:deleted"}, evicted)
+
+	c.Flush()
+	casecheck.Equal(t, int64(0), c.Cost())
+}
+
+func TestUnit_OptMaxCostSharded(t *testing.T) {
+	c := cache.NewSharded[int, []byte](8, func(key int) uint64 { return uint64(key) },
+		cache.OptMaxCost[int, []byte](100),
+		cache.OptCost[int, []byte](func(value []byte) int64 { return int64(len(value)) }),
+	)
+
+	c.Set(0, make([]byte, 50))
+	casecheck.True(t, c.Has(0))
+	casecheck.Equal(t, int64(50), c.Cost())
+
+	c.Set(8, make([]byte, 10))
+	casecheck.False(t, c.Has(0))
+	casecheck.True(t, c.Has(8))
+
+	c.Set(1, make([]byte, 101))
+	casecheck.False(t, c.Has(1))
+
+	for i := 0; i < 8; i++ {
+		c.Set(i, make([]byte, 12))
+	}
+	casecheck.Equal(t, int64(96), c.Cost())
+}
@@ -0,0 +1,222 @@
+/*
+ *  Copyright (c) 2024-2025 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package cache
+
+import (
+	"hash/maphash"
+	"math/bits"
+)
+
+const (
+	segWindow uint8 = iota
+	segProbation
+	segProtected
+)
+
+// tinyLFU is the W-TinyLFU policy: new keys get into a small LRU window,
+// a key evicted from the window is admitted into the segmented LRU main
+// space only if it is used more often than the main victim.
+type tinyLFU[K comparable] struct {
+	items     map[K]*node[K]
+	window    *list[K]
+	probation *list[K]
+	protected *list[K]
+	windowCap int
+	protCap   int
+	sketch    *sketch[K]
+}
+
+func newTinyLFU[K comparable](capacity int) *tinyLFU[K] {
+	windowCap := max(1, capacity/100)
+
+	return &tinyLFU[K]{
+		items:     make(map[K]*node[K], capacity+1),
+		window:    newList[K](),
+		probation: newList[K](),
+		protected: newList[K](),
+		windowCap: windowCap,
+		protCap:   (capacity - windowCap) * 8 / 10,
+		sketch:    newSketch[K](capacity),
+	}
+}
+
+func (v *tinyLFU[K]) segment(seg uint8) *list[K] {
+	switch seg {
+	case segProbation:
+		return v.probation
+	case segProtected:
+		return v.protected
+	default:
+		return v.window
+	}
+}
+
+func (v *tinyLFU[K]) add(key K) {
+	v.sketch.increment(key)
+
+	if n, ok := v.items[key]; ok {
+		v.access(n)
+		return
+	}
+
+	n := &node[K]{key: key, seg: segWindow}
+	v.items[key] = n
+	v.window.pushFront(n)
+
+	for v.window.size > v.windowCap {
+		d := v.window.back()
+		v.window.unlink(d)
+		d.seg = segProbation
+		v.probation.pushFront(d)
+	}
+}
+
+func (v *tinyLFU[K]) touch(key K) {
+	v.sketch.increment(key)
+
+	if n, ok := v.items[key]; ok {
+		v.access(n)
+	}
+}
+
+func (v *tinyLFU[K]) access(n *node[K]) {
+	switch n.seg {
+	case segProbation:
+		v.probation.unlink(n)
+		n.seg = segProtected
+		v.protected.pushFront(n)
+
+		if v.protected.size > v.protCap {
+			if d := v.protected.back(); d != nil && d != n {
+				v.protected.unlink(d)
+				d.seg = segProbation
+				v.probation.pushFront(d)
+			}
+		}
+	default:
+		v.segment(n.seg).moveToFront(n)
+	}
+}
+
+func (v *tinyLFU[K]) remove(key K) {
+	if n, ok := v.items[key]; ok {
+		v.segment(n.seg).unlink(n)
+		delete(v.items, key)
+	}
+}
+
+// victim compares the latest key moved out of the window with the least
+// recently used key of the main space and returns the less frequent one.
+func (v *tinyLFU[K]) victim() (K, bool) {
+	candidate, main := v.probation.front(), v.probation.back()
+	if main == candidate {
+		main = v.protected.back()
+	}
+
+	switch {
+	case candidate != nil && main != nil:
+		if v.sketch.estimate(candidate.key) > v.sketch.estimate(main.key) {
+			return main.key, true
+		}
+		return candidate.key, true
+	case candidate != nil:
+		return candidate.key, true
+	}
+
+	for _, n := range []*node[K]{v.protected.back(), v.window.back()} {
+		if n != nil {
+			return n.key, true
+		}
+	}
+
+	var zero K
+	return zero, false
+}
+
+func (v *tinyLFU[K]) reset() {
+	clear(v.items)
+	v.window.clear()
+	v.probation.clear()
+	v.protected.clear()
+	v.sketch.reset()
+}
+
+// sketch is a count-min sketch with 4-bit counters and periodic aging.
+type sketch[K comparable] struct {
+	table     []uint64
+	mask      uint64
+	seed      maphash.Seed
+	additions int
+	sample    int
+}
+
+func newSketch[K comparable](capacity int) *sketch[K] {
+	size := 1 << bits.Len(uint(max(capacity, 16)-1))
+
+	return &sketch[K]{
+		table:  make([]uint64, size),
+		mask:   uint64(size - 1),
+		seed:   maphash.MakeSeed(),
+		sample: 10 * max(capacity, 16),
+	}
+}
+
+func (v *sketch[K]) indexes(key K) (h uint64, idx [4]uint64) {
+	h = maphash.Comparable(v.seed, key)
+	lo, hi := h, bits.RotateLeft64(h, 32)|1
+	for i := range idx {
+		idx[i] = (lo + uint64(i)*hi) & v.mask
+	}
+	return
+}
+
+// shift returns the offset of the i-th row counter in its 64-bit word.
+func shift(h uint64, i int) uint64 {
+	return ((h >> (8 * i)) & 0xF) << 2
+}
+
+func (v *sketch[K]) increment(key K) {
+	h, idx := v.indexes(key)
+
+	added := false
+	for i, j := range idx {
+		off := shift(h, i)
+		if (v.table[j]>>off)&0xF < 15 {
+			v.table[j] += 1 << off
+			added = true
+		}
+	}
+
+	if added {
+		v.additions++
+		if v.additions >= v.sample {
+			v.age()
+		}
+	}
+}
+
+func (v *sketch[K]) estimate(key K) uint64 {
+	h, idx := v.indexes(key)
+
+	result := uint64(15)
+	for i, j := range idx {
+		result = min(result, (v.table[j]>>shift(h, i))&0xF)
+	}
+	return result
+}
+
+// age halves all counters.
+func (v *sketch[K]) age() {
+	for i := range v.table {
+		v.table[i] = (v.table[i] >> 1) & 0x7777777777777777
+	}
+	v.additions /= 2
+}
+
+func (v *sketch[K]) reset() {
+	clear(v.table)
+	v.additions = 0
+}
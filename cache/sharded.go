@@ -0,0 +1,274 @@
+/*
+ *  Copyright (c) 2024-2025 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package cache
+
+import (
+	"context"
+	"hash/maphash"
+	"io"
+	"iter"
+	"math/rand/v2"
+	"time"
+)
+
+type _sharded[K comparable, V any] struct {
+	shards []*_cache[K, V]
+	hash   func(K) uint64
+	done   <-chan struct{}
+}
+
+// NewSharded creates the cache split into shards with own locks. The hash
+// selects the shard of the key, maphash.Comparable is used if it is nil.
+// Capacity limits of options are divided between shards and every shard
+// evicts by its own part, so a sharded cache may keep fewer elements than the
+// limit when keys are not spread evenly. OptLRU and OptTinyLFU panic if the
+// capacity is less than 16 per shard.
+func NewSharded[K comparable, V any](shards int, hash func(K) uint64, opts ...Option[K, V]) Cache[K, V] {
+	if shards <= 0 {
+		panic("NewSharded: shards <= 0")
+	}
+
+	if hash == nil {
+		seed := maphash.MakeSeed()
+		hash = func(key K) uint64 {
+			return maphash.Comparable(seed, key)
+		}
+	}
+
+	obj := &_sharded[K, V]{
+		shards: make([]*_cache[K, V], shards),
+		hash:   hash,
+	}
+
+	for i := range obj.shards {
+		obj.shards[i] = newCache(shards, opts...)
+	}
+
+	obj.done = persist[K, V](obj.shards[0].snapshot, obj)
+
+	return obj
+}
+
+func (v *_sharded[K, V]) shard(key K) *_cache[K, V] {
+	return v.shards[v.hash(key)%uint64(len(v.shards))]
+}
+
+func (v *_sharded[K, V]) Has(key K) bool {
+	return v.shard(key).Has(key)
+}
+
+func (v *_sharded[K, V]) Get(key K) (V, bool) {
+	return v.shard(key).Get(key)
+}
+
+func (v *_sharded[K, V]) Extract(key K) (V, bool) {
+	return v.shard(key).Extract(key)
+}
+
+func (v *_sharded[K, V]) Set(key K, value V) {
+	v.shard(key).Set(key, value)
+}
+
+func (v *_sharded[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+	v.shard(key).SetWithTTL(key, value, ttl)
+}
+
+func (v *_sharded[K, V]) SetWithCost(key K, value V, cost int64) {
+	v.shard(key).SetWithCost(key, value, cost)
+}
+
+func (v *_sharded[K, V]) Cost() int64 {
+	var n int64
+	for _, s := range v.shards {
+		n += s.Cost()
+	}
+
+	return n
+}
+
+func (v *_sharded[K, V]) Compute(key K, call func(old V, ok bool) (V, bool)) (V, bool) {
+	return v.shard(key).Compute(key, call)
+}
+
+func (v *_sharded[K, V]) GetOrSet(key K, value V) (V, bool) {
+	return v.shard(key).GetOrSet(key, value)
+}
+
+func (v *_sharded[K, V]) SetIfAbsent(key K, value V) bool {
+	return v.shard(key).SetIfAbsent(key, value)
+}
+
+func (v *_sharded[K, V]) update(key K, call func(old V, ok bool) (V, action)) (V, bool) {
+	return v.shard(key).update(key, call)
+}
+
+func (v *_sharded[K, V]) TTL(key K) (time.Duration, bool) {
+	return v.shard(key).TTL(key)
+}
+
+func (v *_sharded[K, V]) Touch(key K, ttl time.Duration) bool {
+	return v.shard(key).Touch(key, ttl)
+}
+
+func (v *_sharded[K, V]) Del(key K) {
+	v.shard(key).Del(key)
+}
+
+func (v *_sharded[K, V]) GetOrLoad(ctx context.Context, key K, loader Loader[K, V]) (V, error) {
+	return v.shard(key).GetOrLoad(ctx, key, loader)
+}
+
+// GetOrLoadAll calls the loader once with missing keys of all shards
+func (v *_sharded[K, V]) GetOrLoadAll(ctx context.Context, keys []K, loader BulkLoader[K, V]) (map[K]V, error) {
+	type part struct {
+		calls map[K]*call[V]
+		own   []K
+	}
+
+	result := make(map[K]V, len(keys))
+	missing := make(map[*_cache[K, V]][]K, len(v.shards))
+
+	for _, key := range keys {
+		s := v.shard(key)
+		if val, ok := s.Get(key); ok {
+			result[key] = val
+			continue
+		}
+		missing[s] = append(missing[s], key)
+	}
+
+	parts := make(map[*_cache[K, V]]part, len(missing))
+	own := make([]K, 0, len(keys))
+	for s, list := range missing {
+		calls, keys := s.reserve(list)
+		parts[s] = part{calls: calls, own: keys}
+		own = append(own, keys...)
+	}
+
+	if len(own) > 0 {
+		go func(ctx context.Context) {
+			values, err := recovered(loader)(ctx, own)
+			for s, p := range parts {
+				if len(p.own) > 0 {
+					s.finish(p.own, p.calls, values, err)
+				}
+			}
+		}(context.WithoutCancel(ctx))
+	}
+
+	for _, p := range parts {
+		if err := collect(ctx, p.calls, result); err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+func (v *_sharded[K, V]) Replace(data map[K]V) {
+	parts := make([]map[K]V, len(v.shards))
+	for i := range parts {
+		parts[i] = make(map[K]V, len(data)/len(v.shards))
+	}
+
+	for key, value := range data {
+		parts[v.hash(key)%uint64(len(v.shards))][key] = value
+	}
+
+	for i, s := range v.shards {
+		s.Replace(parts[i])
+	}
+}
+
+func (v *_sharded[K, V]) One() (key K, val V, ok bool) {
+	n := len(v.shards)
+	from := rand.IntN(n)
+
+	for i := 0; i < n; i++ {
+		if key, val, ok = v.shards[(from+i)%n].One(); ok {
+			return
+		}
+	}
+
+	return
+}
+
+func (v *_sharded[K, V]) Keys() []K {
+	result := make([]K, 0, v.Size())
+	for _, s := range v.shards {
+		result = append(result, s.Keys()...)
+	}
+
+	return result
+}
+
+func (v *_sharded[K, V]) Size() int {
+	n := 0
+	for _, s := range v.shards {
+		n += s.Size()
+	}
+
+	return n
+}
+
+func (v *_sharded[K, V]) Flush() {
+	for _, s := range v.shards {
+		s.Flush()
+	}
+}
+
+func (v *_sharded[K, V]) Snapshot(w io.Writer) error {
+	items := make([]snapshotItem[K, V], 0, v.Size())
+	for _, s := range v.shards {
+		items = append(items, s.items()...)
+	}
+
+	return writeSnapshot(w, v.shards[0].snapshot.ext, items)
+}
+
+func (v *_sharded[K, V]) Done() <-chan struct{} {
+	return v.done
+}
+
+func (v *_sharded[K, V]) Restore(r io.Reader) error {
+	items, err := readSnapshot[K, V](r, v.shards[0].snapshot.ext)
+	if err != nil {
+		return err
+	}
+
+	parts := make([][]snapshotItem[K, V], len(v.shards))
+	for _, si := range items {
+		i := v.hash(si.Key) % uint64(len(v.shards))
+		parts[i] = append(parts[i], si)
+	}
+
+	for i, s := range v.shards {
+		s.restore(parts[i])
+	}
+
+	return nil
+}
+
+func (v *_sharded[K, V]) Yield(limit int) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		n := 0
+		for _, s := range v.shards {
+			next := 0
+			if limit > 0 {
+				if n >= limit {
+					return
+				}
+				next = limit - n
+			}
+			for key, val := range s.Yield(next) {
+				if !yield(key, val) {
+					return
+				}
+				n++
+			}
+		}
+	}
+}
@@ -0,0 +1,161 @@
+/*
+ *  Copyright (c) 2024-2025 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package cache_test
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+
+	"go.osspkg.com/casecheck"
+
+	"go.osspkg.com/ioutils/cache"
+)
+
+func TestUnit_NewSharded(t *testing.T) {
+	c := cache.NewSharded[int, int](4, nil)
+
+	_, _, ok := c.One()
+	casecheck.False(t, ok)
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				c.Set(i*100+j, j)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	casecheck.Equal(t, 800, c.Size())
+	casecheck.Equal(t, 800, len(c.Keys()))
+
+	key, val, ok := c.One()
+	casecheck.True(t, ok)
+	casecheck.Equal(t, key%100, val)
+
+	n := 0
+	for range c.Yield(50) {
+		n++
+	}
+	casecheck.Equal(t, 50, n)
+
+	n = 0
+	for range c.Yield(0) {
+		n++
+	}
+	casecheck.Equal(t, 800, n)
+
+	val, ok = c.Extract(101)
+	casecheck.True(t, ok)
+	casecheck.Equal(t, 1, val)
+	casecheck.False(t, c.Has(101))
+
+	c.Replace(map[int]int{1: 1, 2: 2, 3: 3})
+	keys := c.Keys()
+	sort.Ints(keys)
+	casecheck.Equal(t, []int{1, 2, 3}, keys)
+
+	result, err := c.GetOrLoadAll(context.TODO(), []int{1, 2, 3, 4, 5}, func(_ context.Context, keys []int) (map[int]int, error) {
+		result := make(map[int]int, len(keys))
+		for _, key := range keys {
+			result[key] = key
+		}
+		return result, nil
+	})
+	casecheck.NoError(t, err)
+	casecheck.Equal(t, map[int]int{1: 1, 2: 2, 3: 3, 4: 4, 5: 5}, result)
+
+	c.Flush()
+	casecheck.Equal(t, 0, c.Size())
+}
+
+func TestUnit_NewShardedGetOrLoadAll(t *testing.T) {
+	c := cache.NewSharded[int, int](4, func(key int) uint64 { return uint64(key) })
+	c.Set(1, 1)
+
+	calls := 0
+	var loaded []int
+	result, err := c.GetOrLoadAll(context.TODO(), []int{1, 2, 3, 4, 5, 6}, func(_ context.Context, keys []int) (map[int]int, error) {
+		calls++
+		loaded = append(loaded, keys...)
+		result := make(map[int]int, len(keys))
+		for _, key := range keys {
+			if key != 6 {
+				result[key] = key * 10
+			}
+		}
+		return result, nil
+	})
+	casecheck.NoError(t, err)
+	casecheck.Equal(t, 1, calls)
+	sort.Ints(loaded)
+	casecheck.Equal(t, []int{2, 3, 4, 5, 6}, loaded)
+	casecheck.Equal(t, map[int]int{1: 1, 2: 20, 3: 30, 4: 40, 5: 50}, result)
+	val, ok := c.Get(5)
+	casecheck.True(t, ok)
+	casecheck.Equal(t, 50, val)
+	casecheck.False(t, c.Has(6))
+}
+
+func TestUnit_NewShardedCapacity(t *testing.T) {
+	c := cache.NewSharded[int, int](4, func(key int) uint64 { return uint64(key) },
+		cache.OptLRU[int, int](64))
+
+	for i := 0; i < 100; i++ {
+		c.Set(i, i)
+	}
+
+	casecheck.Equal(t, 64, c.Size())
+	for i := 36; i < 100; i++ {
+		casecheck.True(t, c.Has(i))
+	}
+
+	// every shard keeps 16 elements, the keys of one shard evict each other
+	// while the other shards are empty
+	c.Flush()
+	for i := 0; i < 64; i++ {
+		c.Set(i*4, i)
+	}
+	casecheck.Equal(t, 16, c.Size())
+
+	for _, opt := range []cache.Option[int, int]{
+		cache.OptLRU[int, int](63),
+		cache.OptTinyLFU[int, int](63),
+	} {
+		func() {
+			defer func() {
+				casecheck.NotNil(t, recover())
+			}()
+			cache.NewSharded[int, int](4, nil, opt)
+		}()
+	}
+}
+
+func Benchmark_Sharded(b *testing.B) {
+	for name, c := range map[string]cache.Cache[int, int]{
+		"single":  cache.New[int, int](),
+		"sharded": cache.NewSharded[int, int](64, nil),
+	} {
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			b.RunParallel(func(pb *testing.PB) {
+				i := 0
+				for pb.Next() {
+					c.Set(i%1024, i)
+					c.Get((i + 1) % 1024)
+					i++
+				}
+			})
+		})
+	}
+}
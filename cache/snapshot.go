@@ -0,0 +1,216 @@
+/*
+ *  Copyright (c) 2024-2025 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package cache
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"go.osspkg.com/routine"
+
+	"go.osspkg.com/ioutils/codec"
+	"go.osspkg.com/ioutils/fs"
+)
+
+type (
+	snapshot[K comparable, V any] struct {
+		Items []snapshotItem[K, V] `json:"items" yaml:"items"`
+	}
+
+	snapshotItem[K comparable, V any] struct {
+		Key   K `json:"key" yaml:"key"`
+		Value V `json:"value" yaml:"value"`
+		// Expire is zero if the element never expires
+		Expire time.Time `json:"expire" yaml:"expire"`
+		Cost   int64     `json:"cost" yaml:"cost"`
+	}
+
+	snapshotConfig struct {
+		ext      string
+		ctx      context.Context
+		filename string
+		interval time.Duration
+		onError  func(error)
+	}
+)
+
+func (v *_cache[K, V]) Snapshot(w io.Writer) error {
+	return writeSnapshot(w, v.snapshot.ext, v.items())
+}
+
+func (v *_cache[K, V]) Restore(r io.Reader) error {
+	items, err := readSnapshot[K, V](r, v.snapshot.ext)
+	if err != nil {
+		return err
+	}
+
+	v.restore(items)
+
+	return nil
+}
+
+// items copies not expired elements with absolute expiration time
+func (v *_cache[K, V]) items() []snapshotItem[K, V] {
+	v.mux.RLock()
+	defer v.mux.RUnlock()
+
+	now := time.Now().UnixNano()
+
+	result := make([]snapshotItem[K, V], 0, len(v.list))
+	for key, item := range v.list {
+		if item.expired(now) {
+			continue
+		}
+		si := snapshotItem[K, V]{Key: key, Value: item.val, Cost: item.cost}
+		if item.exp > 0 {
+			si.Expire = time.Unix(0, item.exp)
+		}
+		result = append(result, si)
+	}
+
+	return result
+}
+
+// restore stores elements which are not expired yet
+func (v *_cache[K, V]) restore(items []snapshotItem[K, V]) {
+	defer v.dispatch()
+
+	v.mux.Lock()
+	defer v.mux.Unlock()
+
+	now := time.Now().UnixNano()
+
+	for _, si := range items {
+		item := v.entry(si.Value, 0)
+		if !si.Expire.IsZero() {
+			item.exp = si.Expire.UnixNano()
+		}
+		if si.Cost > 0 {
+			item.cost = si.Cost
+		}
+		if item.expired(now) {
+			continue
+		}
+		v.set(si.Key, item)
+	}
+}
+
+// writeSnapshot encodes by the codec without join, which is lossy for numbers in JSON
+func writeSnapshot[K comparable, V any](w io.Writer, ext string, items []snapshotItem[K, V]) error {
+	c, err := codec.GetCodec(ext)
+	if err != nil {
+		return fmt.Errorf("get codec: %w", err)
+	}
+
+	b, err := c.Encode(&snapshot[K, V]{Items: items})
+	if err != nil {
+		return fmt.Errorf("encode snapshot: %w", err)
+	}
+
+	if _, err = w.Write(b); err != nil {
+		return fmt.Errorf("write snapshot: %w", err)
+	}
+
+	return nil
+}
+
+func readSnapshot[K comparable, V any](r io.Reader, ext string) ([]snapshotItem[K, V], error) {
+	c, err := codec.GetCodec(ext)
+	if err != nil {
+		return nil, fmt.Errorf("get codec: %w", err)
+	}
+
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("read snapshot: %w", err)
+	}
+
+	if len(b) == 0 {
+		return nil, nil
+	}
+
+	snap := &snapshot[K, V]{}
+	if err = c.Decode(b, snap); err != nil {
+		return nil, fmt.Errorf("decode snapshot: %w", err)
+	}
+
+	return snap.Items, nil
+}
+
+var closedDone = func() chan struct{} {
+	c := make(chan struct{})
+	close(c)
+	return c
+}()
+
+// persist restores the cache from the snapshot file and writes it periodically
+// and on the context done, the returned channel is closedDone after the last write
+func persist[K comparable, V any](conf snapshotConfig, c Cache[K, V]) <-chan struct{} {
+	if len(conf.filename) == 0 {
+		return closedDone
+	}
+
+	onError := func(err error) {
+		if conf.onError != nil {
+			conf.onError(err)
+		}
+	}
+
+	if err := restoreFile(conf.filename, c); err != nil {
+		onError(err)
+	}
+
+	tik := routine.Ticker{
+		Interval: conf.interval,
+		OnStart:  false,
+		Calls: []routine.TickFunc{
+			func(context.Context, time.Time) {
+				if err := snapshotFile(conf.filename, c); err != nil {
+					onError(err)
+				}
+			},
+		},
+	}
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		tik.Run(conf.ctx)
+
+		if err := snapshotFile(conf.filename, c); err != nil {
+			onError(err)
+		}
+	}()
+
+	return done
+}
+
+func (v *_cache[K, V]) Done() <-chan struct{} {
+	return v.done
+}
+
+func restoreFile[K comparable, V any](filename string, c Cache[K, V]) error {
+	if !fs.FileExist(filename) {
+		return nil
+	}
+
+	r, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer r.Close() // nolint: errcheck
+
+	return c.Restore(r)
+}
+
+func snapshotFile[K comparable, V any](filename string, c Cache[K, V]) error {
+	return fs.WriteFileAtomic(filename, 0644, c.Snapshot)
+}
@@ -0,0 +1,105 @@
+/*
+ *  Copyright (c) 2024-2025 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package cache_test
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.osspkg.com/casecheck"
+
+	"go.osspkg.com/ioutils/cache"
+	"go.osspkg.com/ioutils/codec"
+)
+
+func TestUnit_SnapshotRestore(t *testing.T) {
+	for _, ext := range []string{codec.ExtGob, codec.ExtJSON, codec.ExtYAML} {
+		t.Run(ext, func(t *testing.T) {
+			c := cache.New[string, testValue](cache.OptSnapshotCodec[string, testValue](ext))
+			c.Set("foo", testValue{TS: 1})
+			c.SetWithTTL("bar", testValue{TS: 2}, time.Hour)
+			c.SetWithTTL("old", testValue{TS: 3}, time.Millisecond)
+			time.Sleep(5 * time.Millisecond)
+
+			var b bytes.Buffer
+			casecheck.NoError(t, c.Snapshot(&b))
+
+			r := cache.NewSharded[string, testValue](2, nil, cache.OptSnapshotCodec[string, testValue](ext))
+			casecheck.NoError(t, r.Restore(&b))
+			casecheck.Equal(t, 2, r.Size())
+
+			val, ok := r.Get("foo")
+			casecheck.True(t, ok)
+			casecheck.Equal(t, int64(1), val.TS)
+
+			ttl, ok := r.TTL("foo")
+			casecheck.True(t, ok)
+			casecheck.Equal(t, time.Duration(0), ttl)
+
+			ttl, ok = r.TTL("bar")
+			casecheck.True(t, ok)
+			casecheck.True(t, ttl > 59*time.Minute && ttl <= time.Hour)
+
+			casecheck.False(t, r.Has("old"))
+		})
+	}
+}
+
+func TestUnit_OptSnapshotFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "cache.json")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c := cache.New[string, int](
+		cache.OptSnapshotCodec[string, int](codec.ExtJSON),
+		cache.OptSnapshotFile[string, int](ctx, filename, time.Hour, func(err error) {
+			casecheck.NoError(t, err)
+		}),
+	)
+	c.Set("foo", 1)
+	cancel()
+	<-c.Done()
+
+	ctx, cancel = context.WithCancel(context.Background())
+	c = cache.New[string, int](
+		cache.OptSnapshotCodec[string, int](codec.ExtJSON),
+		cache.OptSnapshotFile[string, int](ctx, filename, time.Hour, nil),
+	)
+	defer func() {
+		cancel()
+		<-c.Done()
+	}()
+
+	val, ok := c.Get("foo")
+	casecheck.True(t, ok)
+	casecheck.Equal(t, 1, val)
+
+	files, err := filepath.Glob(filepath.Join(filepath.Dir(filename), "*"))
+	casecheck.NoError(t, err)
+	casecheck.Equal(t, []string{filename}, files)
+}
+
+func TestUnit_SnapshotJSONInt64(t *testing.T) {
+	c := cache.New[string, int64](cache.OptSnapshotCodec[string, int64](codec.ExtJSON))
+	c.Set("max", math.MaxInt64)
+	c.Set("big", 1<<53+1)
+
+	var b bytes.Buffer
+	casecheck.NoError(t, c.Snapshot(&b))
+
+	r := cache.New[string, int64](cache.OptSnapshotCodec[string, int64](codec.ExtJSON))
+	casecheck.NoError(t, r.Restore(&b))
+
+	val, _ := r.Get("max")
+	casecheck.Equal(t, int64(math.MaxInt64), val)
+	val, _ = r.Get("big")
+	casecheck.Equal(t, int64(1<<53+1), val)
+
+	<-r.Done()
+}
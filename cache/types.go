@@ -5,7 +5,12 @@
 
 package cache
 
-import "iter"
+import (
+	"context"
+	"io"
+	"iter"
+	"time"
+)
 
 type Cache[K comparable, V any] interface {
 	Has(K) bool
@@ -17,12 +22,40 @@ type Cache[K comparable, V any] interface {
 	//One getting one random key-value element
 	One() (K, V, bool)
 	Set(K, V)
+	//SetWithTTL set element which expires after ttl, ttl <= 0 means never
+	SetWithTTL(K, V, time.Duration)
+	//TTL getting time to live of element, zero if element never expires
+	TTL(K) (time.Duration, bool)
+	//Touch set new ttl of existing element
+	Touch(K, time.Duration) bool
+	//GetOrLoad getting element or load it once for all concurrent callers
+	GetOrLoad(context.Context, K, Loader[K, V]) (V, error)
+	//GetOrLoadAll getting elements and load missing ones by one call
+	GetOrLoadAll(context.Context, []K, BulkLoader[K, V]) (map[K]V, error)
+	//SetWithCost set element with explicit cost
+	SetWithCost(K, V, int64)
+	//Cost getting total cost of elements
+	Cost() int64
+	//Compute set result of call under lock, false result deletes element
+	Compute(K, func(old V, ok bool) (V, bool)) (V, bool)
+	//GetOrSet getting existing element or set value, true if element exists
+	GetOrSet(K, V) (V, bool)
+	//SetIfAbsent set value if element does not exist
+	SetIfAbsent(K, V) bool
 	//Replace replace all elements
 	Replace(map[K]V)
 	Del(K)
 	Keys() []K
+	//Size getting count of elements, including expired ones which are not removed yet
 	Size() int
 	Flush()
+	//Snapshot write not expired elements with their expiration time
+	Snapshot(io.Writer) error
+	//Restore add elements from snapshot, expired ones are skipped
+	Restore(io.Reader) error
+	//Done closed after the last snapshot to file is written on the context done,
+	//closed at once without OptSnapshotFile
+	Done() <-chan struct{}
 }
 
 type Option[K comparable, V any] func(*_cache[K, V])
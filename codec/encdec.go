@@ -6,6 +6,8 @@
 package codec
 
 import (
+	"bytes"
+	"encoding/gob"
 	"encoding/json"
 	"encoding/xml"
 
@@ -20,19 +22,34 @@ const (
 	ExtJSON = ".json"
 	ExtToml = ".toml"
 	ExtXML  = ".xml"
+	ExtGob  = ".gob"
 )
 
 var (
 	ErrUnsupportedFormat = errors.New("format is not a supported")
+	ErrMultipleValues    = errors.New("format does not support multiple values")
 
 	_default = newEncoders().
 			Add(".yml", yaml.Marshal, yaml.Unmarshal, BytesJoin).
 			Add(ExtYAML, yaml.Marshal, yaml.Unmarshal, BytesJoin).
 			Add(ExtJSON, json.Marshal, json.Unmarshal, MapJoin).
 			Add(ExtToml, toml.Marshal, toml.Unmarshal, BytesJoin).
-			Add(ExtXML, xml.Marshal, xml.Unmarshal, BytesJoin)
+			Add(ExtXML, xml.Marshal, xml.Unmarshal, BytesJoin).
+			Add(ExtGob, gobMarshal, gobUnmarshal, singleJoin)
 )
 
+func gobMarshal(in interface{}) ([]byte, error) {
+	var b bytes.Buffer
+	if err := gob.NewEncoder(&b).Encode(in); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+func gobUnmarshal(b []byte, out interface{}) error {
+	return gob.NewDecoder(bytes.NewReader(b)).Decode(out)
+}
+
 type (
 	Codec struct {
 		Encode func(in interface{}) ([]byte, error)
@@ -49,6 +66,10 @@ func AddCodec(ext string, c Codec) {
 	_default.Add(ext, c.Encode, c.Decode, c.Join)
 }
 
+func GetCodec(ext string) (Codec, error) {
+	return _default.Get(ext)
+}
+
 func newEncoders() *encoders {
 	return &encoders{
 		list: make(map[string]Codec, 10),
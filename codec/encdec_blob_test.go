@@ -6,6 +6,7 @@
 package codec_test
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 
@@ -93,3 +94,25 @@ func TestFile_Blob_EncodeDecode2_DuplicatKey(t *testing.T) {
 	model02 := &TestData2{}
 	casecheck.Error(t, be.Decode(model01, model02))
 }
+
+func TestFile_Blob_EncodeDecodeGob(t *testing.T) {
+	type TestData struct {
+		AA string
+		BB []int
+	}
+
+	b := &codec.BlobEncoder{
+		Blob: nil,
+		Ext:  codec.ExtGob,
+	}
+	casecheck.NoError(t, b.Encode(&TestData{AA: "123", BB: []int{1, 2}}))
+
+	out := &TestData{}
+	casecheck.NoError(t, b.Decode(out))
+	casecheck.Equal(t, "123", out.AA)
+	casecheck.Equal(t, []int{1, 2}, out.BB)
+
+	err := b.Encode(&TestData{AA: "123"}, &TestData{AA: "456"})
+	casecheck.Error(t, err)
+	casecheck.True(t, errors.Is(err, codec.ErrMultipleValues))
+}
@@ -20,6 +20,19 @@ func BytesJoin(_ Codec, dst *[]byte, src ...[]byte) error {
 	return nil
 }
 
+// singleJoin keeps the only value of binary formats which can not be merged
+func singleJoin(_ Codec, dst *[]byte, src ...[]byte) error {
+	if len(src) == 0 {
+		return nil
+	}
+	if len(*dst) > 0 || len(src) > 1 {
+		return ErrMultipleValues
+	}
+
+	*dst = append(*dst, src[0]...)
+	return nil
+}
+
 func MapJoin(c Codec, dst *[]byte, src ...[]byte) error {
 	out := map[string]interface{}{}
 
@@ -10,17 +10,23 @@ import (
 	"io"
 
 	"go.osspkg.com/errors"
+
+	"go.osspkg.com/ioutils/pool"
 )
 
 const packSize = 512
 
+var bytesPool = pool.NewSizedPool[byte](packSize, 1<<20)
+
 func Copy(w io.Writer, r io.Reader) (int, error) {
 	return CopyN(w, r, packSize)
 }
 
 func CopyN(w io.Writer, r io.Reader, size int) (int, error) {
 	n := 0
-	buff := make([]byte, size)
+	buf := bytesPool.Get(size)
+	defer bytesPool.Put(buf)
+	buff := buf.B
 	for {
 		m, err1 := r.Read(buff)
 		if m < 0 {
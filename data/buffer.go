@@ -16,8 +16,17 @@ import (
 const packSize = 65535
 
 type Buffer struct {
-	buf []byte
-	pos int
+	buf   []byte
+	pos   int
+	marks []int
+	// seqs are the tokens of marks, seq is the last issued token
+	seqs []int
+	seq  int
+	// gap [gs:ge) in buf is left by InsertAt and DeleteRange and closed
+	// before any other access
+	gs, ge int
+	// edits counts changes of written data, appends are not counted
+	edits int
 }
 
 func NewBuffer(size int) *Buffer {
@@ -41,9 +50,15 @@ func (v *Buffer) Reset() {
 	}
 
 	v.pos = 0
+	v.marks = v.marks[:0]
+	v.seqs = v.seqs[:0]
+	v.gs, v.ge = 0, 0
+	v.edits++
 }
 
 func (v *Buffer) Bytes() []byte {
+	v.flat()
+
 	return v.buf[:]
 }
 
@@ -52,14 +67,20 @@ func (v *Buffer) String() string {
 }
 
 func (v *Buffer) Size() int {
-	return len(v.buf)
+	return len(v.buf) - (v.ge - v.gs)
+}
+
+func (v *Buffer) Cap() int {
+	return cap(v.buf)
 }
 
 func (v *Buffer) Len() int {
-	return len(v.buf) - v.pos
+	return v.Size() - v.pos
 }
 
 func (v *Buffer) Truncate(c int) {
+	v.flat()
+
 	if c <= 0 {
 		return
 	}
@@ -70,6 +91,8 @@ func (v *Buffer) Truncate(c int) {
 		return
 	}
 
+	v.edits++
+
 	v.buf = v.buf[:n]
 
 	for i := 1; i <= 3 && n-i >= 0; i++ {
@@ -87,9 +110,17 @@ func (v *Buffer) Truncate(c int) {
 	if v.pos > n {
 		v.pos = n
 	}
+
+	for i := range v.marks {
+		if v.marks[i] > n {
+			v.marks[i] = n
+		}
+	}
 }
 
 func (v *Buffer) Write(p []byte) (int, error) {
+	v.flat()
+
 	v.buf = append(v.buf, p...)
 
 	return len(p), nil
@@ -100,12 +131,16 @@ func (v *Buffer) WriteString(s string) (int, error) {
 }
 
 func (v *Buffer) WriteByte(b byte) error {
+	v.flat()
+
 	v.buf = append(v.buf, b)
 
 	return nil
 }
 
 func (v *Buffer) WriteRune(r rune) (n int, err error) {
+	v.flat()
+
 	n = v.Size()
 	v.buf = utf8.AppendRune(v.buf, r)
 	n = v.Size() - n
@@ -160,10 +195,14 @@ func (v *Buffer) WriteToN(w io.Writer, size int) (n int64, err error) {
 }
 
 func (v *Buffer) WriteAt(b []byte, off int64) (int, error) {
+	v.flat()
+
 	if off < 0 {
 		off = 0
 	}
 
+	v.edits++
+
 	if add := len(b) + int(off) - v.Size(); add > 0 {
 		v.buf = append(v.buf, make([]byte, add)...)
 	}
@@ -178,6 +217,8 @@ func (v *Buffer) ReadFrom(r io.Reader) (int64, error) {
 }
 
 func (v *Buffer) ReadFromN(r io.Reader, size int) (int64, error) {
+	v.flat()
+
 	n := 0
 	b := make([]byte, size)
 
@@ -203,6 +244,8 @@ func (v *Buffer) ReadFromN(r io.Reader, size int) (int64, error) {
 }
 
 func (v *Buffer) Read(p []byte) (int, error) {
+	v.flat()
+
 	if len(p) == 0 {
 		return 0, fmt.Errorf("got zero buffer")
 	}
@@ -217,6 +260,8 @@ func (v *Buffer) Read(p []byte) (int, error) {
 }
 
 func (v *Buffer) ReadAt(p []byte, off int64) (int, error) {
+	v.flat()
+
 	if len(p) == 0 {
 		return 0, fmt.Errorf("got zero buffer")
 	}
@@ -251,6 +296,8 @@ func (v *Buffer) Resume(n int) int {
 }
 
 func (v *Buffer) Next(n int) []byte {
+	v.flat()
+
 	if n <= 0 {
 		return nil
 	}
@@ -271,6 +318,8 @@ func (v *Buffer) Next(n int) []byte {
 }
 
 func (v *Buffer) ReadByte() (byte, error) {
+	v.flat()
+
 	if v.Len() == 0 {
 		return 0, io.EOF
 	}
@@ -292,6 +341,8 @@ func (v *Buffer) UnreadByte() error {
 }
 
 func (v *Buffer) ReadRune() (rune, int, error) {
+	v.flat()
+
 	if v.Len() == 0 {
 		return 0, 0, io.EOF
 	}
@@ -303,6 +354,8 @@ func (v *Buffer) ReadRune() (rune, int, error) {
 }
 
 func (v *Buffer) UnreadRune() error {
+	v.flat()
+
 	if v.pos <= 0 {
 		return fmt.Errorf("at beginning")
 	}
@@ -323,6 +376,8 @@ func (v *Buffer) UnreadRune() error {
 }
 
 func (v *Buffer) ReadBytes(delim byte) ([]byte, error) {
+	v.flat()
+
 	if v.Len() == 0 {
 		return nil, io.EOF
 	}
@@ -340,6 +395,8 @@ func (v *Buffer) ReadBytes(delim byte) ([]byte, error) {
 }
 
 func (v *Buffer) ReadNextBytes(delim []byte) ([]byte, error) {
+	v.flat()
+
 	if v.Len() == 0 {
 		return nil, io.EOF
 	}
@@ -358,6 +415,8 @@ func (v *Buffer) ReadNextBytes(delim []byte) ([]byte, error) {
 }
 
 func (v *Buffer) NextField(sep string, accurate bool) (field []byte, symbol []byte, err error) {
+	v.flat()
+
 	if v.Len() == 0 {
 		return nil, nil, io.EOF
 	}
@@ -0,0 +1,166 @@
+/*
+ *  Copyright (c) 2024-2025 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package data
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+func (v *Buffer) AppendInt(i int64, base int) {
+	v.flat()
+
+	v.buf = strconv.AppendInt(v.buf, i, base)
+}
+
+func (v *Buffer) AppendUint(i uint64, base int) {
+	v.flat()
+
+	v.buf = strconv.AppendUint(v.buf, i, base)
+}
+
+func (v *Buffer) AppendFloat(f float64, fmt byte, prec, bitSize int) {
+	v.flat()
+
+	v.buf = strconv.AppendFloat(v.buf, f, fmt, prec, bitSize)
+}
+
+func (v *Buffer) AppendBool(b bool) {
+	v.flat()
+
+	v.buf = strconv.AppendBool(v.buf, b)
+}
+
+// AppendQuoted writes a double-quoted Go string literal.
+func (v *Buffer) AppendQuoted(s string) {
+	v.flat()
+
+	v.buf = strconv.AppendQuote(v.buf, s)
+}
+
+func (v *Buffer) AppendTime(t time.Time, layout string) {
+	v.flat()
+
+	v.buf = t.AppendFormat(v.buf, layout)
+}
+
+func (v *Buffer) AppendHex(b []byte) {
+	v.flat()
+
+	v.buf = hex.AppendEncode(v.buf, b)
+}
+
+// AppendBase64 writes b with the enc encoding or base64.StdEncoding if enc is nil.
+func (v *Buffer) AppendBase64(b []byte, enc *base64.Encoding) {
+	v.flat()
+
+	if enc == nil {
+		enc = base64.StdEncoding
+	}
+	v.buf = enc.AppendEncode(v.buf, b)
+}
+
+const hexDigits = "0123456789abcdef"
+
+// AppendJSONString writes s as a double-quoted JSON string. Invalid UTF-8
+// is replaced with U+FFFD, HTML-sensitive runes are not escaped.
+func (v *Buffer) AppendJSONString(s string) {
+	v.flat()
+
+	v.buf = append(v.buf, '"')
+
+	start := 0
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			if c >= 0x20 && c != '"' && c != '\\' {
+				i++
+				continue
+			}
+
+			v.buf = append(v.buf, s[start:i]...)
+			switch c {
+			case '"', '\\':
+				v.buf = append(v.buf, '\\', c)
+			case '\n':
+				v.buf = append(v.buf, '\\', 'n')
+			case '\r':
+				v.buf = append(v.buf, '\\', 'r')
+			case '\t':
+				v.buf = append(v.buf, '\\', 't')
+			default:
+				v.buf = append(v.buf, '\\', 'u', '0', '0', hexDigits[c>>4], hexDigits[c&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+
+		r, n := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && n == 1 {
+			v.buf = append(v.buf, s[start:i]...)
+			v.buf = append(v.buf, "\ufffd"...)
+			i += n
+			start = i
+			continue
+		}
+
+		if r == '\u2028' || r == '\u2029' {
+			v.buf = append(v.buf, s[start:i]...)
+			v.buf = append(v.buf, '\\', 'u', '2', '0', '2', hexDigits[r&0xF])
+			i += n
+			start = i
+			continue
+		}
+
+		i += n
+	}
+
+	v.buf = append(v.buf, s[start:]...)
+	v.buf = append(v.buf, '"')
+}
+
+// AppendRepeat writes the byte c n times.
+func (v *Buffer) AppendRepeat(c byte, n int) {
+	v.flat()
+
+	for ; n > 0; n-- {
+		v.buf = append(v.buf, c)
+	}
+}
+
+// PadLeft pads the bytes written since the offset from with the byte c
+// on the left up to width bytes.
+func (v *Buffer) PadLeft(from, width int, c byte) {
+	v.flat()
+
+	from = max(0, min(from, v.Size()))
+
+	add := width - (v.Size() - from)
+	if add <= 0 {
+		return
+	}
+
+	v.AppendRepeat(c, add)
+	v.edits++
+	copy(v.buf[from+add:], v.buf[from:v.Size()-add])
+	for i := from; i < from+add; i++ {
+		v.buf[i] = c
+	}
+}
+
+// PadRight pads the bytes written since the offset from with the byte c
+// on the right up to width bytes.
+func (v *Buffer) PadRight(from, width int, c byte) {
+	v.flat()
+
+	from = max(0, min(from, v.Size()))
+
+	v.AppendRepeat(c, width-(v.Size()-from))
+}
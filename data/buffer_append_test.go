@@ -0,0 +1,100 @@
+/*
+ *  Copyright (c) 2024-2025 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package data
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"go.osspkg.com/casecheck"
+)
+
+func TestUnit_Append(t *testing.T) {
+	d := NewBuffer(10)
+
+	d.AppendInt(-42, 10)
+	d.WriteByte(' ')
+	d.AppendUint(255, 16)
+	d.WriteByte(' ')
+	d.AppendFloat(1.5, 'f', 2, 64)
+	d.WriteByte(' ')
+	d.AppendBool(true)
+	d.WriteByte(' ')
+	d.AppendQuoted("a\"b")
+	d.WriteByte(' ')
+	d.AppendTime(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), time.RFC3339)
+	d.WriteByte(' ')
+	d.AppendHex([]byte{0xde, 0xad})
+	d.WriteByte(' ')
+	d.AppendBase64([]byte("hi"), nil)
+
+	casecheck.Equal(t, `-42 ff 1.50 true "a\"b" 2024-01-02T03:04:05Z dead aGk=`, d.String())
+}
+
+func TestUnit_AppendJSONString(t *testing.T) {
+	tests := []string{
+		"",
+		"simple",
+		"q\"b\\s/",
+		"ctl\n\r\t\x00\x1f",
+		"юникод 卉",
+		"bad\xffutf8",
+		"sep\u2028\u2029",
+	}
+	for _, tt := range tests {
+		t.Run(tt, func(t *testing.T) {
+			d := NewBuffer(10)
+			d.AppendJSONString(tt)
+
+			var out string
+			casecheck.NoError(t, json.Unmarshal(d.Bytes(), &out))
+
+			exp, err := json.Marshal(tt)
+			casecheck.NoError(t, err)
+			var want string
+			casecheck.NoError(t, json.Unmarshal(exp, &want))
+			casecheck.Equal(t, want, out)
+		})
+	}
+}
+
+func TestUnit_Pad(t *testing.T) {
+	d := NewBuffer(10)
+	d.WriteString("|")
+
+	from := d.Size()
+	d.AppendInt(42, 10)
+	d.PadLeft(from, 5, '0')
+	d.WriteString("|")
+
+	from = d.Size()
+	d.WriteString("ab")
+	d.PadRight(from, 4, '.')
+	d.WriteString("|")
+
+	from = d.Size()
+	d.WriteString("long")
+	d.PadLeft(from, 2, ' ')
+	d.PadRight(from, 2, ' ')
+	d.WriteString("|")
+
+	casecheck.Equal(t, "|00042|ab..|long|", d.String())
+}
+
+func Benchmark_AppendInt(b *testing.B) {
+	d := NewBuffer(1024)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		d.Reset()
+		d.AppendInt(int64(i), 10)
+		d.AppendJSONString("metric\tname")
+		d.AppendFloat(1.5, 'f', -1, 64)
+	}
+}
@@ -0,0 +1,191 @@
+/*
+ *  Copyright (c) 2024-2025 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package data
+
+import (
+	"bytes"
+	"slices"
+	"unicode/utf8"
+)
+
+const gapSize = 64
+
+func (v *Buffer) flat() {
+	if v.gs == v.ge {
+		return
+	}
+
+	n := copy(v.buf[v.gs:], v.buf[v.ge:])
+	v.buf = v.buf[:v.gs+n]
+	v.gs, v.ge = 0, 0
+}
+
+func (v *Buffer) moveGap(off int) {
+	switch {
+	case v.gs == v.ge:
+		v.gs, v.ge = off, off
+	case off < v.gs:
+		n := v.gs - off
+		copy(v.buf[v.ge-n:v.ge], v.buf[off:v.gs])
+		v.gs, v.ge = off, v.ge-n
+	case off > v.gs:
+		n := off - v.gs
+		copy(v.buf[v.gs:v.gs+n], v.buf[v.ge:v.ge+n])
+		v.gs, v.ge = off, v.ge+n
+	}
+}
+
+func (v *Buffer) growGap(need int) {
+	if v.ge-v.gs >= need {
+		return
+	}
+
+	add := max(need-(v.ge-v.gs), gapSize, len(v.buf)>>3)
+	tail := len(v.buf) - v.ge
+
+	v.buf = slices.Grow(v.buf, add)[:len(v.buf)+add]
+	copy(v.buf[v.ge+add:], v.buf[v.ge:v.ge+tail])
+	v.ge += add
+}
+
+// byteAt returns the byte at the logical offset ignoring the gap.
+func (v *Buffer) byteAt(off int) byte {
+	if off >= v.gs {
+		off += v.ge - v.gs
+	}
+	return v.buf[off]
+}
+
+// shift moves the read position and the marks after replacing del bytes
+// at off with ins bytes. Positions inside the removed range move to off.
+func (v *Buffer) shift(off, del, ins int) {
+	v.edits++
+
+	adjust := func(p int) int {
+		switch {
+		case p <= off:
+			return p
+		case p < off+del:
+			return off
+		default:
+			return p - del + ins
+		}
+	}
+
+	v.pos = adjust(v.pos)
+	for i := range v.marks {
+		v.marks[i] = adjust(v.marks[i])
+	}
+}
+
+// InsertAt inserts p before the byte at off. The read position and marks
+// after off are moved, the ones equal to off stay before the inserted data.
+func (v *Buffer) InsertAt(off int, p []byte) {
+	if len(p) == 0 {
+		return
+	}
+
+	off = max(0, min(off, v.Size()))
+
+	v.moveGap(off)
+	v.growGap(len(p))
+	copy(v.buf[v.gs:], p)
+	v.gs += len(p)
+
+	v.shift(off, 0, len(p))
+}
+
+// DeleteRange deletes n bytes from off and returns the number of deleted bytes.
+func (v *Buffer) DeleteRange(off, n int) int {
+	off = max(0, min(off, v.Size()))
+	n = max(0, min(n, v.Size()-off))
+	if n == 0 {
+		return 0
+	}
+
+	v.moveGap(off)
+	v.ge += n
+
+	v.shift(off, n, 0)
+
+	return n
+}
+
+// ReplaceAll replaces all non-overlapping instances of old with new
+// and returns the number of replacements.
+func (v *Buffer) ReplaceAll(old, new []byte) int {
+	if len(old) == 0 {
+		return 0
+	}
+
+	v.flat()
+	v.moveGap(0)
+
+	count := 0
+	for {
+		i := bytes.Index(v.buf[v.ge:], old)
+		if i < 0 {
+			break
+		}
+
+		off := v.gs + i
+		v.moveGap(off)
+		v.ge += len(old)
+		v.growGap(len(new))
+		copy(v.buf[v.gs:], new)
+		v.gs += len(new)
+
+		v.shift(off, len(old), len(new))
+		count++
+	}
+
+	return count
+}
+
+func (v *Buffer) runeStart(off int) int {
+	for i := 0; i < utf8.UTFMax-1 && off > 0 && off < v.Size(); i++ {
+		if utf8.RuneStart(v.byteAt(off)) {
+			break
+		}
+		off--
+	}
+	return off
+}
+
+// InsertAtUTF8 works as InsertAt but moves off back to the rune start so
+// a multibyte rune is never split. It returns the used offset.
+func (v *Buffer) InsertAtUTF8(off int, p []byte) int {
+	off = v.runeStart(max(0, min(off, v.Size())))
+	v.InsertAt(off, p)
+	return off
+}
+
+// DeleteRangeUTF8 works as DeleteRange but widens the range to whole runes.
+// It returns the used offset and the number of deleted bytes.
+func (v *Buffer) DeleteRangeUTF8(off, n int) (int, int) {
+	off = max(0, min(off, v.Size()))
+	if n <= 0 {
+		return off, 0
+	}
+
+	end := min(off+n, v.Size())
+	if s := v.runeStart(end); s < end {
+		_, rn := v.runeAt(s)
+		end = max(end, s+rn)
+	}
+	off = v.runeStart(off)
+
+	return off, v.DeleteRange(off, end-off)
+}
+
+func (v *Buffer) runeAt(off int) (rune, int) {
+	var b [utf8.UTFMax]byte
+	n := 0
+	for ; n < len(b) && off+n < v.Size(); n++ {
+		b[n] = v.byteAt(off + n)
+	}
+	return utf8.DecodeRune(b[:n])
+}
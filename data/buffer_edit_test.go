@@ -0,0 +1,125 @@
+/*
+ *  Copyright (c) 2024-2025 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package data
+
+import (
+	"bytes"
+	"testing"
+
+	"go.osspkg.com/casecheck"
+)
+
+func TestUnit_InsertDelete(t *testing.T) {
+	d := NewBuffer(10)
+	d.WriteString("hello world")
+	d.Seek(6, SeekStart)
+	m := d.Mark()
+
+	d.InsertAt(5, []byte(","))
+	casecheck.Equal(t, 12, d.Size())
+	casecheck.Equal(t, 5, d.Len())
+
+	d.InsertAt(6, []byte(" big"))
+	d.InsertAt(100, []byte("!"))
+	casecheck.Equal(t, "hello, big world!", d.String())
+	casecheck.Equal(t, "world!", string(d.Next(100)))
+
+	casecheck.NoError(t, d.Rewind(m))
+	casecheck.Equal(t, "world!", string(d.Next(100)))
+
+	d.Seek(8, SeekStart)
+	casecheck.Equal(t, 4, d.DeleteRange(6, 4))
+	casecheck.Equal(t, "hello, world!", d.String())
+	casecheck.Equal(t, " world!", string(d.Next(100)))
+
+	casecheck.Equal(t, 1, d.DeleteRange(12, 10))
+	casecheck.Equal(t, 0, d.DeleteRange(-1, 0))
+	casecheck.Equal(t, "hello, world", d.String())
+
+	d.Reset()
+	d.WriteString("abc")
+	d.InsertAt(0, []byte("0"))
+	d.WriteString("d")
+	casecheck.Equal(t, "0abcd", d.String())
+}
+
+func TestUnit_GapEdits(t *testing.T) {
+	d := NewBuffer(10)
+	exp := []byte("0123456789")
+	d.Write(exp)
+
+	for i := 0; i < 200; i++ {
+		off := (i * 7) % (len(exp) + 1)
+		p := []byte{'a' + byte(i%26)}
+
+		d.InsertAt(off, p)
+		exp = append(exp[:off], append(p, exp[off:]...)...)
+
+		if i%3 == 0 {
+			d.DeleteRange(off/2, 2)
+			exp = append(exp[:off/2], exp[min(off/2+2, len(exp)):]...)
+		}
+
+		casecheck.Equal(t, len(exp), d.Size())
+	}
+
+	casecheck.Equal(t, string(exp), d.String())
+}
+
+func TestUnit_ReplaceAll(t *testing.T) {
+	d := NewBuffer(10)
+	d.WriteString("{{a}} and {{a}} or {{b}}")
+	d.Seek(11, SeekStart)
+
+	casecheck.Equal(t, 2, d.ReplaceAll([]byte("{{a}}"), []byte("alpha")))
+	casecheck.Equal(t, "alpha and alpha or {{b}}", d.String())
+	casecheck.Equal(t, 10, d.Size()-d.Len())
+
+	casecheck.Equal(t, 1, d.ReplaceAll([]byte("{{b}}"), nil))
+	casecheck.Equal(t, 0, d.ReplaceAll(nil, []byte("x")))
+	casecheck.Equal(t, "alpha and alpha or ", d.String())
+
+	long := bytes.Repeat([]byte("ab"), 1000)
+	d.Reset()
+	d.Write(long)
+	casecheck.Equal(t, 1000, d.ReplaceAll([]byte("b"), []byte("ccc")))
+	casecheck.Equal(t, string(bytes.ReplaceAll(long, []byte("b"), []byte("ccc"))), d.String())
+}
+
+func TestUnit_EditUTF8(t *testing.T) {
+	d := NewBuffer(10)
+	d.WriteString("aФ卉b")
+
+	casecheck.Equal(t, 1, d.InsertAtUTF8(2, []byte("-")))
+	casecheck.Equal(t, "a-Ф卉b", d.String())
+
+	off, n := d.DeleteRangeUTF8(5, 2)
+	casecheck.Equal(t, 4, off)
+	casecheck.Equal(t, 3, n)
+	casecheck.Equal(t, "a-Фb", d.String())
+
+	off, n = d.DeleteRangeUTF8(3, 1)
+	casecheck.Equal(t, 2, off)
+	casecheck.Equal(t, 2, n)
+	casecheck.Equal(t, "a-b", d.String())
+
+	off, n = d.DeleteRangeUTF8(1, 0)
+	casecheck.Equal(t, 1, off)
+	casecheck.Equal(t, 0, n)
+}
+
+func Benchmark_InsertAt(b *testing.B) {
+	d := NewBuffer(1024)
+	d.Write(bytes.Repeat([]byte("x"), 1<<20))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		d.InsertAt(1000+i%16, []byte("y"))
+		d.DeleteRange(1000+i%16, 1)
+	}
+}
@@ -0,0 +1,143 @@
+/*
+ *  Copyright (c) 2024-2025 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package data
+
+import (
+	"bytes"
+	"iter"
+	"strings"
+	"unicode/utf8"
+)
+
+type (
+	iterConfig struct {
+		keepDelim bool
+		trimCR    bool
+	}
+
+	IterOption func(*iterConfig)
+)
+
+// OptKeepDelim keeps the delimiter at the end of every token.
+func OptKeepDelim() IterOption {
+	return func(c *iterConfig) {
+		c.keepDelim = true
+	}
+}
+
+// OptTrimCR treats a '\r' right before the delimiter or at the end of the
+// final token as a part of the delimiter.
+func OptTrimCR() IterOption {
+	return func(c *iterConfig) {
+		c.trimCR = true
+	}
+}
+
+func newIterConfig(opts []IterOption) iterConfig {
+	conf := iterConfig{}
+	for _, opt := range opts {
+		opt(&conf)
+	}
+	return conf
+}
+
+// Lines iterates over the lines from the current position. The read position
+// is moved past every token before it is yielded, the final unterminated line
+// is yielded as is.
+func (v *Buffer) Lines(opts ...IterOption) iter.Seq[[]byte] {
+	return v.Split([]byte{'\n'}, opts...)
+}
+
+func (v *Buffer) Split(delim []byte, opts ...IterOption) iter.Seq[[]byte] {
+	conf := newIterConfig(opts)
+
+	return func(yield func([]byte) bool) {
+		for v.Len() > 0 {
+			v.flat()
+
+			start := v.pos
+			end, next := v.Size(), v.Size()
+
+			if len(delim) > 0 {
+				if i := bytes.Index(v.buf[start:], delim); i >= 0 {
+					end = start + i
+					next = end + len(delim)
+				}
+			}
+
+			if conf.trimCR && end > start && v.buf[end-1] == '\r' {
+				end--
+			}
+
+			if conf.keepDelim {
+				end = next
+			}
+
+			v.pos = next
+
+			if !yield(v.buf[start:end]) {
+				return
+			}
+		}
+	}
+}
+
+// Fields iterates over the non-empty tokens separated by any of the runes
+// from seps.
+func (v *Buffer) Fields(seps string, opts ...IterOption) iter.Seq[[]byte] {
+	conf := newIterConfig(opts)
+
+	return func(yield func([]byte) bool) {
+		for v.Len() > 0 {
+			v.flat()
+
+			start := v.pos
+			end, next := v.Size(), v.Size()
+
+			if i := bytes.IndexAny(v.buf[start:], seps); i >= 0 {
+				_, n := utf8.DecodeRune(v.buf[start+i:])
+				end = start + i
+				next = end + n
+			}
+
+			if conf.trimCR && end > start && v.buf[end-1] == '\r' && !strings.ContainsRune(seps, '\r') {
+				end--
+			}
+
+			v.pos = next
+
+			if end == start {
+				continue
+			}
+
+			if conf.keepDelim {
+				end = next
+			}
+
+			if !yield(v.buf[start:end]) {
+				return
+			}
+		}
+	}
+}
+
+// Runes iterates over the runes from the current position and yields
+// the offset of every rune.
+func (v *Buffer) Runes() iter.Seq2[int, rune] {
+	return func(yield func(int, rune) bool) {
+		for v.Len() > 0 {
+			v.flat()
+
+			off := v.pos
+			r, n := utf8.DecodeRune(v.buf[off:])
+			v.pos += n
+
+			if !yield(off, r) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,82 @@
+/*
+ *  Copyright (c) 2024-2025 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package data
+
+import (
+	"iter"
+	"testing"
+
+	"go.osspkg.com/casecheck"
+)
+
+func collect(seq iter.Seq[[]byte]) []string {
+	result := make([]string, 0, 4)
+	for b := range seq {
+		result = append(result, string(b))
+	}
+	return result
+}
+
+func TestUnit_Lines(t *testing.T) {
+	tests := []struct {
+		in   string
+		opts []IterOption
+		out  []string
+	}{
+		{in: "", out: []string{}},
+		{in: "a\nb\r\n\nc", out: []string{"a", "b\r", "", "c"}},
+		{in: "a\nb\r\n\nc\r", opts: []IterOption{OptTrimCR()}, out: []string{"a", "b", "", "c"}},
+		{in: "a\nb\r\n\nc", opts: []IterOption{OptKeepDelim()}, out: []string{"a\n", "b\r\n", "\n", "c"}},
+		{in: "a\nb\r\n", opts: []IterOption{OptKeepDelim(), OptTrimCR()}, out: []string{"a\n", "b\r\n"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			d := NewBuffer(10)
+			d.WriteString(tt.in)
+			casecheck.Equal(t, tt.out, collect(d.Lines(tt.opts...)))
+			casecheck.Equal(t, 0, d.Len())
+		})
+	}
+}
+
+func TestUnit_SplitBreak(t *testing.T) {
+	d := NewBuffer(10)
+	d.WriteString("aa::bb::cc")
+
+	for b := range d.Split([]byte("::")) {
+		casecheck.Equal(t, "aa", string(b))
+		break
+	}
+	casecheck.Equal(t, "bb::cc", string(d.Next(100)))
+
+	d.Seek(0, SeekStart)
+	casecheck.Equal(t, []string{"aa::", "bb::", "cc"}, collect(d.Split([]byte("::"), OptKeepDelim())))
+}
+
+func TestUnit_Fields(t *testing.T) {
+	d := NewBuffer(10)
+	d.WriteString("  123  456\t`789`\r")
+
+	casecheck.Equal(t, []string{"123", "456", "789"}, collect(d.Fields(" \t`", OptTrimCR())))
+
+	d.Seek(0, SeekStart)
+	casecheck.Equal(t, []string{"123 ", "456\t", "789`", "\r"}, collect(d.Fields(" \t`", OptKeepDelim())))
+}
+
+func TestUnit_Runes(t *testing.T) {
+	d := NewBuffer(10)
+	d.WriteString("aФ卉")
+
+	offs := make([]int, 0, 3)
+	runes := make([]rune, 0, 3)
+	for off, r := range d.Runes() {
+		offs = append(offs, off)
+		runes = append(runes, r)
+	}
+	casecheck.Equal(t, []int{0, 1, 3}, offs)
+	casecheck.Equal(t, []rune("aФ卉"), runes)
+	casecheck.Equal(t, 0, d.Len())
+}
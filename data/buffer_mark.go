@@ -0,0 +1,91 @@
+/*
+ *  Copyright (c) 2024-2025 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package data
+
+import "errors"
+
+var ErrInvalidMark = errors.New("invalid mark")
+
+// Mark is a checkpoint of the read position. Marks are nested: committing or
+// rewinding a mark also releases all marks taken after it. A released mark
+// stays invalid even if new marks are taken.
+type Mark int
+
+func (v *Buffer) Mark() Mark {
+	v.seq++
+	v.marks = append(v.marks, v.pos)
+	v.seqs = append(v.seqs, v.seq)
+
+	return Mark(v.seq)
+}
+
+// index returns the index of the live mark or -1
+func (v *Buffer) index(m Mark) int {
+	for i := len(v.seqs) - 1; i >= 0; i-- {
+		if v.seqs[i] == int(m) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Rewind restores the read position saved by the mark and releases it.
+func (v *Buffer) Rewind(m Mark) error {
+	i := v.index(m)
+	if i < 0 {
+		return ErrInvalidMark
+	}
+
+	v.pos = v.marks[i]
+	v.marks = v.marks[:i]
+	v.seqs = v.seqs[:i]
+
+	return nil
+}
+
+// Commit releases the mark and keeps the current read position.
+func (v *Buffer) Commit(m Mark) error {
+	i := v.index(m)
+	if i < 0 {
+		return ErrInvalidMark
+	}
+
+	v.marks = v.marks[:i]
+	v.seqs = v.seqs[:i]
+
+	return nil
+}
+
+func (v *Buffer) Marks() int {
+	return len(v.marks)
+}
+
+// Compact drops the bytes before the read position or the earliest live mark
+// and returns the number of dropped bytes. Live marks stay valid.
+func (v *Buffer) Compact() int {
+	v.flat()
+
+	off := v.pos
+	for _, m := range v.marks {
+		off = min(off, m)
+	}
+
+	if off <= 0 {
+		return 0
+	}
+
+	n := copy(v.buf, v.buf[off:])
+	v.buf = v.buf[:n]
+	v.edits++
+	v.pos -= off
+
+	for i := range v.marks {
+		v.marks[i] -= off
+	}
+
+	return off
+}
@@ -0,0 +1,116 @@
+/*
+ *  Copyright (c) 2024-2025 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package data
+
+import (
+	"errors"
+	"testing"
+
+	"go.osspkg.com/casecheck"
+)
+
+func TestUnit_MarkRewind(t *testing.T) {
+	d := NewBuffer(10)
+	d.WriteString("key: value\nkey2: val")
+
+	m1 := d.Mark()
+	b, err := d.ReadBytes('\n')
+	casecheck.NoError(t, err)
+	casecheck.Equal(t, "key: value\n", string(b))
+	casecheck.NoError(t, d.Commit(m1))
+	casecheck.Equal(t, 0, d.Marks())
+
+	m1 = d.Mark()
+	f, _, err := d.NextField(":", true)
+	casecheck.NoError(t, err)
+	casecheck.Equal(t, "key2", string(f))
+
+	m2 := d.Mark()
+	casecheck.Equal(t, 2, d.Marks())
+	b, err = d.ReadBytes('\n')
+	casecheck.NoError(t, err)
+	casecheck.Equal(t, " val", string(b))
+
+	casecheck.NoError(t, d.Rewind(m2))
+	casecheck.Equal(t, 1, d.Marks())
+	casecheck.Equal(t, 4, d.Len())
+
+	casecheck.NoError(t, d.Rewind(m1))
+	casecheck.Equal(t, 0, d.Marks())
+	casecheck.Equal(t, "key2: val", string(d.Next(100)))
+
+	casecheck.Error(t, d.Rewind(m1))
+	casecheck.Error(t, d.Commit(m2))
+	casecheck.Error(t, d.Commit(0))
+
+	d.Seek(2, SeekStart)
+	m3 := d.Mark()
+	d.Seek(0, SeekStart)
+	casecheck.True(t, errors.Is(d.Rewind(m1), ErrInvalidMark))
+	casecheck.Equal(t, 1, d.Marks())
+	casecheck.NoError(t, d.Rewind(m3))
+	casecheck.Equal(t, 2, d.Size()-d.Len())
+}
+
+func TestUnit_MarkNestedRelease(t *testing.T) {
+	d := NewBuffer(10)
+	d.WriteString("0123456789")
+
+	m1 := d.Mark()
+	d.Discard(2)
+	d.Mark()
+	d.Discard(2)
+	d.Mark()
+	d.Discard(2)
+
+	casecheck.Equal(t, 3, d.Marks())
+	casecheck.NoError(t, d.Commit(m1))
+	casecheck.Equal(t, 0, d.Marks())
+	casecheck.Equal(t, 4, d.Len())
+}
+
+func TestUnit_Compact(t *testing.T) {
+	d := NewBuffer(10)
+	d.WriteString("aaa\nbbb\nccc")
+
+	_, err := d.ReadBytes('\n')
+	casecheck.NoError(t, err)
+
+	m := d.Mark()
+	_, err = d.ReadBytes('\n')
+	casecheck.NoError(t, err)
+
+	casecheck.Equal(t, 4, d.Compact())
+	casecheck.Equal(t, "bbb\nccc", d.String())
+	casecheck.Equal(t, 3, d.Len())
+
+	casecheck.NoError(t, d.Rewind(m))
+	casecheck.Equal(t, "bbb\n", string(d.Next(4)))
+
+	casecheck.Equal(t, 4, d.Compact())
+	casecheck.Equal(t, "ccc", d.String())
+	casecheck.Equal(t, 0, d.Compact())
+
+	d.Seek(0, SeekEnd)
+	casecheck.Equal(t, 3, d.Compact())
+	casecheck.Equal(t, 0, d.Size())
+	casecheck.Equal(t, 0, d.Len())
+}
+
+func TestUnit_MarkTruncateReset(t *testing.T) {
+	d := NewBuffer(10)
+	d.WriteString("0123456789")
+	d.Seek(8, SeekStart)
+	m := d.Mark()
+
+	d.Truncate(5)
+	casecheck.NoError(t, d.Rewind(m))
+	casecheck.Equal(t, 0, d.Len())
+
+	d.Mark()
+	d.Reset()
+	casecheck.Equal(t, 0, d.Marks())
+}
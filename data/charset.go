@@ -0,0 +1,315 @@
+/*
+ *  Copyright (c) 2024-2025 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package data
+
+import (
+	"errors"
+	"io"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+type (
+	// Charset converts text between an encoding and UTF-8.
+	Charset interface {
+		// NewReader decodes r into UTF-8
+		NewReader(r io.Reader) io.Reader
+		// NewWriter encodes UTF-8 into w, Close flushes a trailing incomplete
+		// rune and does not close w
+		NewWriter(w io.Writer) io.WriteCloser
+	}
+
+	singleByte struct {
+		table *[128]rune
+		index map[rune]byte
+	}
+
+	utf16Charset struct {
+		bigEndian bool
+		bom       bool
+	}
+)
+
+var (
+	Latin1      Charset = newSingleByte(nil)
+	Windows1251 Charset = newSingleByte(&windows1251Table)
+	Windows1252 Charset = newSingleByte(&windows1252Table)
+	KOI8R       Charset = newSingleByte(&koi8rTable)
+	CP866       Charset = newSingleByte(&cp866Table)
+
+	// UTF16LE, UTF16BE and UTF16 readers detect and skip the byte order mark,
+	// UTF16 writer puts the little-endian byte order mark.
+	UTF16LE Charset = utf16Charset{bigEndian: false}
+	UTF16BE Charset = utf16Charset{bigEndian: true}
+	UTF16   Charset = utf16Charset{bigEndian: false, bom: true}
+)
+
+const unknownByte = '?'
+
+func newSingleByte(table *[128]rune) *singleByte {
+	v := &singleByte{table: table}
+	if table != nil {
+		v.index = make(map[rune]byte, len(table))
+		for i, r := range table {
+			if r != utf8.RuneError {
+				v.index[r] = byte(0x80 + i)
+			}
+		}
+	}
+	return v
+}
+
+func (v *singleByte) decode(dst, src []byte, _ bool) ([]byte, int) {
+	for _, c := range src {
+		switch {
+		case c < utf8.RuneSelf:
+			dst = append(dst, c)
+		case v.table == nil:
+			dst = utf8.AppendRune(dst, rune(c))
+		default:
+			dst = utf8.AppendRune(dst, v.table[c-0x80])
+		}
+	}
+	return dst, len(src)
+}
+
+func (v *singleByte) encode(dst []byte, r rune) []byte {
+	switch {
+	case r < utf8.RuneSelf:
+		return append(dst, byte(r))
+	case v.table == nil && r <= 0xFF:
+		return append(dst, byte(r))
+	}
+
+	if c, ok := v.index[r]; ok {
+		return append(dst, c)
+	}
+	return append(dst, unknownByte)
+}
+
+func (v *singleByte) NewReader(r io.Reader) io.Reader {
+	return &decodeReader{r: r, decode: v.decode}
+}
+
+func (v *singleByte) NewWriter(w io.Writer) io.WriteCloser {
+	return &encodeWriter{w: w, encode: v.encode}
+}
+
+func (v utf16Charset) NewReader(r io.Reader) io.Reader {
+	d := &utf16Decoder{bigEndian: v.bigEndian}
+	return &decodeReader{r: r, decode: d.decode}
+}
+
+func (v utf16Charset) NewWriter(w io.Writer) io.WriteCloser {
+	ew := &encodeWriter{w: w, encode: v.encode}
+	if v.bom {
+		ew.head = v.encode(nil, 0xFEFF)
+	}
+	return ew
+}
+
+func (v utf16Charset) encode(dst []byte, r rune) []byte {
+	var units [2]uint16
+	n := 1
+	if r1, r2 := utf16.EncodeRune(r); r1 != utf8.RuneError {
+		units[0], units[1], n = uint16(r1), uint16(r2), 2
+	} else {
+		if r > 0xFFFF || (r >= 0xD800 && r < 0xE000) {
+			r = utf8.RuneError
+		}
+		units[0] = uint16(r)
+	}
+
+	for _, u := range units[:n] {
+		if v.bigEndian {
+			dst = append(dst, byte(u>>8), byte(u))
+		} else {
+			dst = append(dst, byte(u), byte(u>>8))
+		}
+	}
+	return dst
+}
+
+type utf16Decoder struct {
+	bigEndian bool
+	started   bool
+}
+
+func (v *utf16Decoder) unit(b []byte) uint16 {
+	if v.bigEndian {
+		return uint16(b[0])<<8 | uint16(b[1])
+	}
+	return uint16(b[1])<<8 | uint16(b[0])
+}
+
+func (v *utf16Decoder) decode(dst, src []byte, eof bool) ([]byte, int) {
+	i := 0
+
+	if !v.started {
+		if len(src) < 2 && !eof {
+			return dst, 0
+		}
+		v.started = true
+		if len(src) >= 2 {
+			switch {
+			case src[0] == 0xFF && src[1] == 0xFE:
+				v.bigEndian, i = false, 2
+			case src[0] == 0xFE && src[1] == 0xFF:
+				v.bigEndian, i = true, 2
+			}
+		}
+	}
+
+	for ; i+1 < len(src); i += 2 {
+		u := v.unit(src[i:])
+		if !utf16.IsSurrogate(rune(u)) {
+			dst = utf8.AppendRune(dst, rune(u))
+			continue
+		}
+
+		if i+3 >= len(src) && !eof {
+			return dst, i
+		}
+
+		r := utf8.RuneError
+		if i+3 < len(src) {
+			if r = utf16.DecodeRune(rune(u), rune(v.unit(src[i+2:]))); r != utf8.RuneError {
+				i += 2
+			}
+		}
+		dst = utf8.AppendRune(dst, r)
+	}
+
+	if eof && i < len(src) {
+		dst = utf8.AppendRune(dst, utf8.RuneError)
+		i = len(src)
+	}
+
+	return dst, i
+}
+
+type decodeReader struct {
+	r      io.Reader
+	decode func(dst, src []byte, eof bool) ([]byte, int)
+	src    []byte
+	dst    []byte
+	off    int
+	err    error
+}
+
+func (v *decodeReader) Read(p []byte) (int, error) {
+	for v.off >= len(v.dst) {
+		if v.err != nil {
+			return 0, v.err
+		}
+
+		if len(v.src) == cap(v.src) {
+			v.src = append(v.src, make([]byte, 512)...)[:len(v.src)]
+		}
+
+		n, err := v.r.Read(v.src[len(v.src):cap(v.src)])
+		v.src = v.src[:len(v.src)+n]
+		if err != nil {
+			v.err = err
+		}
+
+		var m int
+		v.dst, m = v.decode(v.dst[:0], v.src, errors.Is(err, io.EOF))
+		v.off = 0
+		v.src = v.src[:copy(v.src, v.src[m:])]
+	}
+
+	n := copy(p, v.dst[v.off:])
+	v.off += n
+
+	return n, nil
+}
+
+type encodeWriter struct {
+	w      io.Writer
+	encode func(dst []byte, r rune) []byte
+	head   []byte
+	tail   []byte
+	out    []byte
+}
+
+func (v *encodeWriter) Write(p []byte) (int, error) {
+	v.out = append(v.out[:0], v.head...)
+	v.head = nil
+
+	b := p
+	if len(v.tail) > 0 {
+		b = append(v.tail, p...)
+	}
+
+	i := 0
+	for i < len(b) {
+		if !utf8.FullRune(b[i:]) {
+			break
+		}
+		r, n := utf8.DecodeRune(b[i:])
+		v.out = v.encode(v.out, r)
+		i += n
+	}
+	v.tail = append(v.tail[:0:0], b[i:]...)
+
+	if _, err := v.w.Write(v.out); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+func (v *encodeWriter) Close() error {
+	v.out = append(v.out[:0], v.head...)
+	v.head = nil
+
+	if len(v.tail) > 0 {
+		v.out = v.encode(v.out, utf8.RuneError)
+		v.tail = v.tail[:0]
+	}
+
+	if len(v.out) == 0 {
+		return nil
+	}
+
+	_, err := v.w.Write(v.out)
+	return err
+}
+
+func (v *Buffer) ValidUTF8() bool {
+	v.flat()
+
+	return utf8.Valid(v.buf)
+}
+
+// RepairUTF8 replaces every run of invalid UTF-8 bytes with repl
+// and returns the number of replaced runs.
+func (v *Buffer) RepairUTF8(repl string) int {
+	v.flat()
+
+	type span struct{ off, n int }
+	spans := make([]span, 0, 2)
+
+	for i := 0; i < len(v.buf); {
+		r, n := utf8.DecodeRune(v.buf[i:])
+		if r == utf8.RuneError && n == 1 {
+			if k := len(spans) - 1; k >= 0 && spans[k].off+spans[k].n == i {
+				spans[k].n++
+			} else {
+				spans = append(spans, span{off: i, n: 1})
+			}
+		}
+		i += n
+	}
+
+	for i := len(spans) - 1; i >= 0; i-- {
+		v.DeleteRange(spans[i].off, spans[i].n)
+		v.InsertAt(spans[i].off, []byte(repl))
+	}
+
+	return len(spans)
+}
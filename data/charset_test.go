@@ -0,0 +1,89 @@
+/*
+ *  Copyright (c) 2024-2025 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package data
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"testing/iotest"
+
+	"go.osspkg.com/casecheck"
+)
+
+func TestUnit_CharsetDecode(t *testing.T) {
+	tests := []struct {
+		name string
+		cs   Charset
+		in   []byte
+		out  string
+	}{
+		{name: "latin1", cs: Latin1, in: []byte{'a', 0xE9, 0xFF}, out: "aéÿ"},
+		{name: "cp1251", cs: Windows1251, in: []byte{0xCF, 0xF0, 0xE8, 0xE2, 0xE5, 0xF2, '!', 0x98}, out: "Привет!�"},
+		{name: "cp1252", cs: Windows1252, in: []byte{0x80, ' ', 0xE9}, out: "€ é"},
+		{name: "koi8r", cs: KOI8R, in: []byte{0xF0, 0xD2, 0xC9}, out: "При"},
+		{name: "cp866", cs: CP866, in: []byte{0x8F, 0xE0, 0xA8}, out: "При"},
+		{name: "utf16le", cs: UTF16LE, in: []byte{0x1F, 0x04, 'a', 0, 0x3D, 0xD8, 0x00, 0xDE}, out: "Пa😀"},
+		{name: "utf16be", cs: UTF16BE, in: []byte{0x04, 0x1F, 0, 'a'}, out: "Пa"},
+		{name: "utf16 bom be", cs: UTF16, in: []byte{0xFE, 0xFF, 0x04, 0x1F, 0, 'a'}, out: "Пa"},
+		{name: "utf16 bom le", cs: UTF16BE, in: []byte{0xFF, 0xFE, 0x1F, 0x04, 'a', 0}, out: "Пa"},
+		{name: "utf16 broken", cs: UTF16LE, in: []byte{0x3D, 0xD8, 'a', 0, 'b'}, out: "�a�"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := tt.cs.NewReader(iotest.OneByteReader(bytes.NewReader(tt.in)))
+			out, err := io.ReadAll(iotest.HalfReader(r))
+			casecheck.NoError(t, err)
+			casecheck.Equal(t, tt.out, string(out))
+		})
+	}
+}
+
+func TestUnit_CharsetEncode(t *testing.T) {
+	tests := []struct {
+		name string
+		cs   Charset
+		in   string
+		out  []byte
+	}{
+		{name: "latin1", cs: Latin1, in: "aéП", out: []byte{'a', 0xE9, '?'}},
+		{name: "cp1251", cs: Windows1251, in: "Привет!", out: []byte{0xCF, 0xF0, 0xE8, 0xE2, 0xE5, 0xF2, '!'}},
+		{name: "koi8r", cs: KOI8R, in: "При", out: []byte{0xF0, 0xD2, 0xC9}},
+		{name: "utf16le", cs: UTF16LE, in: "Пa😀", out: []byte{0x1F, 0x04, 'a', 0, 0x3D, 0xD8, 0x00, 0xDE}},
+		{name: "utf16be", cs: UTF16BE, in: "Пa", out: []byte{0x04, 0x1F, 0, 'a'}},
+		{name: "utf16", cs: UTF16, in: "a", out: []byte{0xFF, 0xFE, 'a', 0}},
+		{name: "broken tail", cs: Windows1251, in: "a\xd0", out: []byte{'a', '?'}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			w := tt.cs.NewWriter(out)
+			for _, c := range []byte(tt.in) {
+				_, err := w.Write([]byte{c})
+				casecheck.NoError(t, err)
+			}
+			casecheck.NoError(t, w.Close())
+			casecheck.Equal(t, tt.out, out.Bytes())
+		})
+	}
+}
+
+func TestUnit_RepairUTF8(t *testing.T) {
+	d := NewBuffer(10)
+	d.WriteString("ok")
+	casecheck.True(t, d.ValidUTF8())
+	casecheck.Equal(t, 0, d.RepairUTF8("?"))
+
+	d.Reset()
+	d.WriteString("a\xff\xfeb\xd0Фc")
+	d.Seek(4, SeekStart)
+	casecheck.False(t, d.ValidUTF8())
+
+	casecheck.Equal(t, 2, d.RepairUTF8("�"))
+	casecheck.True(t, d.ValidUTF8())
+	casecheck.Equal(t, "a�b�Фc", d.String())
+	casecheck.Equal(t, "�Фc", string(d.Next(100)))
+}
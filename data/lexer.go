@@ -0,0 +1,173 @@
+/*
+ *  Copyright (c) 2024-2025 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package data
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+var ErrUnterminatedQuote = errors.New("unterminated quote")
+
+type (
+	// Lexer splits the buffer into fields honouring quotes and escapes.
+	// Fields are unescaped in place, so the raw bytes of a field are
+	// overwritten after it is read. Lexer keeps the state of the last read
+	// field and is not safe for concurrent use.
+	Lexer struct {
+		// Separators runes between fields, " \t\r\n" if empty
+		Separators string
+		// Quotes runes which open and close a quoted part of a field
+		Quotes string
+		// Escape enables backslash escapes inside and outside quotes
+		Escape bool
+		// DoubleQuote treats a doubled quote inside quotes as a literal quote
+		DoubleQuote bool
+		// Comment rune at the field start which skips the rest of the line
+		Comment rune
+		// KeepEmpty returns empty fields between adjacent separators
+		// and after a trailing separator
+		KeepEmpty bool
+
+		// tail is the buffer and the offset after the last separator read
+		// by Next, an empty field is returned there at the end of the buffer
+		tailBuf *Buffer
+		tail    int
+	}
+
+	Field struct {
+		Value []byte
+		// Offset of the raw field in the buffer
+		Offset int
+		// End of the raw field in the buffer, separator excluded
+		End int
+	}
+)
+
+const defaultSeparators = " \t\r\n"
+
+func (l *Lexer) separators() string {
+	if len(l.Separators) == 0 {
+		return defaultSeparators
+	}
+	return l.Separators
+}
+
+func (l *Lexer) Next(b *Buffer) (Field, error) {
+	seps := l.separators()
+	b.flat()
+
+	for {
+		if !l.KeepEmpty {
+			for b.Len() > 0 {
+				r, n := utf8.DecodeRune(b.buf[b.pos:])
+				if !strings.ContainsRune(seps, r) {
+					break
+				}
+				b.pos += n
+			}
+		}
+
+		if b.Len() == 0 {
+			if l.KeepEmpty && l.tailBuf == b && l.tail == b.pos {
+				l.tailBuf = nil
+				return Field{Value: b.buf[b.pos:b.pos], Offset: b.pos, End: b.pos}, nil
+			}
+			return Field{}, io.EOF
+		}
+
+		if l.Comment != 0 {
+			if r, _ := utf8.DecodeRune(b.buf[b.pos:]); r == l.Comment {
+				b.ReadBytes('\n') //nolint: errcheck
+				continue
+			}
+		}
+
+		break
+	}
+
+	start := b.pos
+	end, next, err := l.scan(b.buf, start, seps, false)
+	if err != nil {
+		return Field{}, err
+	}
+
+	n, _, _ := l.scan(b.buf, start, seps, true)
+	b.pos = next
+
+	l.tailBuf = nil
+	if next > end {
+		l.tailBuf, l.tail = b, next
+	}
+
+	return Field{Value: b.buf[start:n], Offset: start, End: end}, nil
+}
+
+// scan walks the raw field from start and returns the end of the raw field and
+// the offset after the separator. When write is set the unescaped field is
+// copied to start and the first value is the end of the unescaped field.
+func (l *Lexer) scan(buf []byte, start int, seps string, write bool) (int, int, error) {
+	var quote rune
+	w, i := start, start
+
+	put := func(from, n int) {
+		if write {
+			copy(buf[w:], buf[from:from+n])
+		}
+		w += n
+	}
+
+	for i < len(buf) {
+		r, n := utf8.DecodeRune(buf[i:])
+
+		switch {
+		case l.Escape && r == '\\' && i+n < len(buf):
+			_, en := utf8.DecodeRune(buf[i+n:])
+			put(i+n, en)
+			i += n + en
+
+		case quote != 0 && r == quote:
+			if l.DoubleQuote && i+n < len(buf) {
+				if r2, _ := utf8.DecodeRune(buf[i+n:]); r2 == quote {
+					put(i, n)
+					i += 2 * n
+					continue
+				}
+			}
+			quote = 0
+			i += n
+
+		case quote != 0:
+			put(i, n)
+			i += n
+
+		case strings.ContainsRune(l.Quotes, r):
+			quote = r
+			i += n
+
+		case strings.ContainsRune(seps, r):
+			if write {
+				return w, i + n, nil
+			}
+			return i, i + n, nil
+
+		default:
+			put(i, n)
+			i += n
+		}
+	}
+
+	if quote != 0 {
+		return 0, 0, ErrUnterminatedQuote
+	}
+
+	if write {
+		return w, i, nil
+	}
+	return i, i, nil
+}
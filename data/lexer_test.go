@@ -0,0 +1,72 @@
+/*
+ *  Copyright (c) 2024-2025 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package data
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"go.osspkg.com/casecheck"
+)
+
+func lexAll(t *testing.T, l *Lexer, in string) ([]string, []int) {
+	d := NewBuffer(10)
+	d.WriteString(in)
+
+	values := make([]string, 0, 4)
+	offsets := make([]int, 0, 4)
+	for {
+		f, err := l.Next(d)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		casecheck.NoError(t, err)
+		values = append(values, string(f.Value))
+		offsets = append(offsets, f.Offset)
+	}
+	return values, offsets
+}
+
+func TestUnit_LexerShell(t *testing.T) {
+	l := &Lexer{Quotes: `"'`, Escape: true, Comment: '#'}
+
+	values, offsets := lexAll(t, l, "key=\"a b\" x='c\\'d'  # comment\n  y=a\\ b\tz")
+	casecheck.Equal(t, []string{"key=a b", "x=c'd", "y=a b", "z"}, values)
+	casecheck.Equal(t, []int{0, 10, 32, 39}, offsets)
+}
+
+func TestUnit_LexerCSV(t *testing.T) {
+	l := &Lexer{Separators: ",", Quotes: `"`, DoubleQuote: true, KeepEmpty: true}
+
+	values, offsets := lexAll(t, l, `a,,"b ""c"", d",Ф`)
+	casecheck.Equal(t, []string{"a", "", `b "c", d`, "Ф"}, values)
+	casecheck.Equal(t, []int{0, 2, 3, 16}, offsets)
+
+	values, offsets = lexAll(t, l, "a,b,")
+	casecheck.Equal(t, []string{"a", "b", ""}, values)
+	casecheck.Equal(t, []int{0, 2, 4}, offsets)
+
+	values, _ = lexAll(t, l, ",")
+	casecheck.Equal(t, []string{"", ""}, values)
+}
+
+func TestUnit_LexerUnterminated(t *testing.T) {
+	l := &Lexer{Quotes: `"`}
+
+	d := NewBuffer(10)
+	d.WriteString(`a "b c`)
+
+	f, err := l.Next(d)
+	casecheck.NoError(t, err)
+	casecheck.Equal(t, "a", string(f.Value))
+	casecheck.Equal(t, 1, f.End)
+
+	_, err = l.Next(d)
+	casecheck.True(t, errors.Is(err, ErrUnterminatedQuote))
+	casecheck.Equal(t, `a "b c`, d.String())
+	casecheck.Equal(t, 4, d.Len())
+}
@@ -0,0 +1,187 @@
+/*
+ *  Copyright (c) 2024-2025 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package data
+
+import (
+	"fmt"
+	"io"
+	"iter"
+	"math"
+	"os"
+)
+
+// MappedBuffer is a read-only Buffer over a file mapped into memory.
+// Slices returned by its methods point to the mapping and must not be
+// used after Close.
+type MappedBuffer struct {
+	b     Buffer
+	unmap func([]byte) error
+}
+
+// NewMappedBuffer maps the whole file, the file can be closed right after it.
+func NewMappedBuffer(f *os.File) (*MappedBuffer, error) {
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	size := fi.Size()
+	if size > math.MaxInt {
+		return nil, fmt.Errorf("file is too large: %d", size)
+	}
+
+	v := &MappedBuffer{}
+	if size == 0 {
+		return v, nil
+	}
+
+	if v.b.buf, v.unmap, err = mapFile(f, int(size)); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+func OpenMappedBuffer(filename string) (*MappedBuffer, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close() // nolint: errcheck
+
+	return NewMappedBuffer(f)
+}
+
+func (v *MappedBuffer) Close() error {
+	b := v.b.buf
+	v.b = Buffer{}
+
+	if v.unmap == nil || b == nil {
+		return nil
+	}
+
+	return v.unmap(b)
+}
+
+func (v *MappedBuffer) Bytes() []byte {
+	return v.b.Bytes()
+}
+
+func (v *MappedBuffer) Size() int {
+	return v.b.Size()
+}
+
+func (v *MappedBuffer) Len() int {
+	return v.b.Len()
+}
+
+func (v *MappedBuffer) Read(p []byte) (int, error) {
+	return v.b.Read(p)
+}
+
+func (v *MappedBuffer) ReadAt(p []byte, off int64) (int, error) {
+	return v.b.ReadAt(p, off)
+}
+
+func (v *MappedBuffer) ReadByte() (byte, error) {
+	return v.b.ReadByte()
+}
+
+func (v *MappedBuffer) UnreadByte() error {
+	return v.b.UnreadByte()
+}
+
+func (v *MappedBuffer) ReadRune() (rune, int, error) {
+	return v.b.ReadRune()
+}
+
+func (v *MappedBuffer) UnreadRune() error {
+	return v.b.UnreadRune()
+}
+
+func (v *MappedBuffer) Next(n int) []byte {
+	return v.b.Next(n)
+}
+
+func (v *MappedBuffer) Discard(n int) int {
+	return v.b.Discard(n)
+}
+
+func (v *MappedBuffer) Resume(n int) int {
+	return v.b.Resume(n)
+}
+
+func (v *MappedBuffer) Seek(offset int64, whence int) (int64, error) {
+	return v.b.Seek(offset, whence)
+}
+
+func (v *MappedBuffer) WriteTo(w io.Writer) (int64, error) {
+	return v.b.WriteTo(w)
+}
+
+func (v *MappedBuffer) WriteToN(w io.Writer, size int) (int64, error) {
+	return v.b.WriteToN(w, size)
+}
+
+func (v *MappedBuffer) ReadBytes(delim byte) ([]byte, error) {
+	return v.b.ReadBytes(delim)
+}
+
+func (v *MappedBuffer) ReadNextBytes(delim []byte) ([]byte, error) {
+	return v.b.ReadNextBytes(delim)
+}
+
+func (v *MappedBuffer) ReadString(delim byte) (string, error) {
+	return v.b.ReadString(delim)
+}
+
+func (v *MappedBuffer) ReadNextString(delim string) (string, error) {
+	return v.b.ReadNextString(delim)
+}
+
+func (v *MappedBuffer) NextField(sep string, accurate bool) ([]byte, []byte, error) {
+	return v.b.NextField(sep, accurate)
+}
+
+func (v *MappedBuffer) ReadUntilAny(m *Matcher, limit int) ([]byte, int, error) {
+	return v.b.ReadUntilAny(m, limit)
+}
+
+func (v *MappedBuffer) Lines(opts ...IterOption) iter.Seq[[]byte] {
+	return v.b.Lines(opts...)
+}
+
+func (v *MappedBuffer) Split(delim []byte, opts ...IterOption) iter.Seq[[]byte] {
+	return v.b.Split(delim, opts...)
+}
+
+func (v *MappedBuffer) Fields(seps string, opts ...IterOption) iter.Seq[[]byte] {
+	return v.b.Fields(seps, opts...)
+}
+
+func (v *MappedBuffer) Runes() iter.Seq2[int, rune] {
+	return v.b.Runes()
+}
+
+func (v *MappedBuffer) Mark() Mark {
+	return v.b.Mark()
+}
+
+func (v *MappedBuffer) Rewind(m Mark) error {
+	return v.b.Rewind(m)
+}
+
+func (v *MappedBuffer) Commit(m Mark) error {
+	return v.b.Commit(m)
+}
+
+func (v *MappedBuffer) Marks() int {
+	return v.b.Marks()
+}
+
+func (v *MappedBuffer) ValidUTF8() bool {
+	return v.b.ValidUTF8()
+}
@@ -0,0 +1,69 @@
+/*
+ *  Copyright (c) 2024-2025 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package data
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.osspkg.com/casecheck"
+)
+
+func TestUnit_MappedBuffer(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "data.log")
+	casecheck.NoError(t, os.WriteFile(filename, []byte("a=1\nb=2\nc=3"), 0644))
+
+	mb, err := OpenMappedBuffer(filename)
+	casecheck.NoError(t, err)
+	casecheck.Equal(t, 11, mb.Size())
+
+	b, err := mb.ReadBytes('\n')
+	casecheck.NoError(t, err)
+	casecheck.Equal(t, "a=1\n", string(b))
+
+	m := mb.Mark()
+	f, s, err := mb.NextField("=", true)
+	casecheck.NoError(t, err)
+	casecheck.Equal(t, "b", string(f))
+	casecheck.Equal(t, "=", string(s))
+	casecheck.NoError(t, mb.Rewind(m))
+
+	lines := make([]string, 0, 2)
+	for line := range mb.Lines() {
+		lines = append(lines, string(line))
+	}
+	casecheck.Equal(t, []string{"b=2", "c=3"}, lines)
+
+	bs := make([]byte, 3)
+	n, err := mb.ReadAt(bs, 8)
+	casecheck.NoError(t, err)
+	casecheck.Equal(t, "c=3", string(bs[:n]))
+
+	_, err = mb.Seek(0, SeekStart)
+	casecheck.NoError(t, err)
+	all, err := io.ReadAll(mb)
+	casecheck.NoError(t, err)
+	casecheck.Equal(t, "a=1\nb=2\nc=3", string(all))
+
+	casecheck.NoError(t, mb.Close())
+	casecheck.Equal(t, 0, mb.Size())
+	casecheck.NoError(t, mb.Close())
+}
+
+func TestUnit_MappedBufferEmpty(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "empty.log")
+	casecheck.NoError(t, os.WriteFile(filename, nil, 0644))
+
+	mb, err := OpenMappedBuffer(filename)
+	casecheck.NoError(t, err)
+	casecheck.Equal(t, 0, mb.Len())
+
+	_, err = mb.ReadBytes('\n')
+	casecheck.Error(t, err)
+	casecheck.NoError(t, mb.Close())
+}
@@ -0,0 +1,168 @@
+/*
+ *  Copyright (c) 2024-2025 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package data
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+var ErrScanLimit = errors.New("scan limit exceeded")
+
+// Matcher searches for several patterns at once with the Aho-Corasick
+// automaton. It is immutable after creation and safe for concurrent use.
+type Matcher struct {
+	delta  [][256]int32
+	out    []int32
+	dict   []int32
+	lens   []int
+	maxLen int
+}
+
+func NewMatcher(patterns ...[]byte) (*Matcher, error) {
+	if len(patterns) == 0 {
+		return nil, fmt.Errorf("got empty patterns list")
+	}
+
+	m := &Matcher{
+		delta: make([][256]int32, 1, 16),
+		out:   []int32{-1},
+		lens:  make([]int, 0, len(patterns)),
+	}
+
+	for i, p := range patterns {
+		if len(p) == 0 {
+			return nil, fmt.Errorf("got empty pattern #%d", i)
+		}
+
+		m.lens = append(m.lens, len(p))
+		m.maxLen = max(m.maxLen, len(p))
+
+		state := int32(0)
+		for _, c := range p {
+			next := m.delta[state][c]
+			if next == 0 {
+				m.delta = append(m.delta, [256]int32{})
+				m.out = append(m.out, -1)
+				next = int32(len(m.delta) - 1)
+				m.delta[state][c] = next
+			}
+			state = next
+		}
+
+		if m.out[state] < 0 {
+			m.out[state] = int32(i)
+		}
+	}
+
+	fail := make([]int32, len(m.delta))
+	m.dict = make([]int32, len(m.delta))
+	m.dict[0] = -1
+
+	queue := make([]int32, 0, len(m.delta))
+	for c := 0; c < 256; c++ {
+		if next := m.delta[0][c]; next != 0 {
+			m.dict[next] = -1
+			queue = append(queue, next)
+		}
+	}
+
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+
+		for c := 0; c < 256; c++ {
+			next := m.delta[state][c]
+			if next == 0 {
+				m.delta[state][c] = m.delta[fail[state]][c]
+				continue
+			}
+
+			f := m.delta[fail[state]][c]
+			fail[next] = f
+			if m.out[f] >= 0 {
+				m.dict[next] = f
+			} else {
+				m.dict[next] = m.dict[f]
+			}
+			queue = append(queue, next)
+		}
+	}
+
+	return m, nil
+}
+
+// Index returns the start of the leftmost match in b and the index of
+// the matched pattern, the longest pattern wins if several start at the same
+// offset. It returns -1, -1 if nothing is found.
+func (m *Matcher) Index(b []byte) (int, int) {
+	start, pattern := -1, -1
+	state := int32(0)
+
+	for i, c := range b {
+		state = m.delta[state][c]
+
+		o := state
+		if m.out[o] < 0 {
+			o = m.dict[o]
+		}
+		for ; o > 0; o = m.dict[o] {
+			p := int(m.out[o])
+			s := i - m.lens[p] + 1
+			if start < 0 || s < start || (s == start && m.lens[p] > m.lens[pattern]) {
+				start, pattern = s, p
+			}
+		}
+
+		if start >= 0 && i+2-m.maxLen > start {
+			break
+		}
+	}
+
+	return start, pattern
+}
+
+// Len returns the length of the pattern.
+func (m *Matcher) Len(pattern int) int {
+	return m.lens[pattern]
+}
+
+// ReadUntilAny reads up to and including the leftmost pattern of the matcher
+// and returns the index of the matched pattern. Without a match the rest of
+// the buffer is returned with the pattern -1. If limit > 0 and no pattern
+// starts in the first limit bytes, ErrScanLimit is returned and the read
+// position is not moved. A pattern starting before limit may end after it.
+func (v *Buffer) ReadUntilAny(m *Matcher, limit int) ([]byte, int, error) {
+	v.flat()
+
+	if v.Len() == 0 {
+		return nil, -1, io.EOF
+	}
+
+	window := v.buf[v.pos:]
+	if limit > 0 && len(window) > limit+m.maxLen-1 {
+		window = window[:limit+m.maxLen-1]
+	}
+
+	end := v.Size()
+
+	i, p := m.Index(window)
+	if limit > 0 && i >= limit {
+		i, p = -1, -1
+	}
+
+	if i >= 0 {
+		end = v.pos + i + m.lens[p]
+	} else if limit > 0 && v.Len() > limit {
+		return nil, -1, ErrScanLimit
+	}
+
+	b := v.buf[v.pos:end]
+	v.pos = end
+
+	return b, p, nil
+}
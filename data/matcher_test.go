@@ -0,0 +1,114 @@
+/*
+ *  Copyright (c) 2024-2025 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package data
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"go.osspkg.com/casecheck"
+)
+
+func TestUnit_MatcherIndex(t *testing.T) {
+	m, err := NewMatcher([]byte("\r\n"), []byte("\n"), []byte("\r\n.\r\n"), []byte("she"), []byte("he"), []byte("hers"))
+	casecheck.NoError(t, err)
+
+	tests := []struct {
+		in       string
+		start, p int
+	}{
+		{in: "", start: -1, p: -1},
+		{in: "abc", start: -1, p: -1},
+		{in: "abc\n", start: 3, p: 1},
+		{in: "abc\r\ndef", start: 3, p: 0},
+		{in: "abc\r\n.\r\n", start: 3, p: 2},
+		{in: "abc\r\n.x", start: 3, p: 0},
+		{in: "ushers", start: 1, p: 3},
+		{in: "uhers", start: 1, p: 5},
+		{in: "\r", start: -1, p: -1},
+	}
+	for _, tt := range tests {
+		start, p := m.Index([]byte(tt.in))
+		casecheck.Equal(t, tt.start, start, tt.in)
+		casecheck.Equal(t, tt.p, p, tt.in)
+	}
+
+	_, err = NewMatcher()
+	casecheck.Error(t, err)
+	_, err = NewMatcher([]byte("a"), nil)
+	casecheck.Error(t, err)
+}
+
+func TestUnit_ReadUntilAny(t *testing.T) {
+	m, err := NewMatcher([]byte("\r\n"), []byte("\n"), []byte("\r\n.\r\n"))
+	casecheck.NoError(t, err)
+
+	d := NewBuffer(10)
+	d.WriteString("HELO x\r\nDATA\nline\r\n.\r\ntail")
+
+	b, p, err := d.ReadUntilAny(m, 0)
+	casecheck.NoError(t, err)
+	casecheck.Equal(t, "HELO x\r\n", string(b))
+	casecheck.Equal(t, 0, p)
+
+	b, p, err = d.ReadUntilAny(m, 0)
+	casecheck.NoError(t, err)
+	casecheck.Equal(t, "DATA\n", string(b))
+	casecheck.Equal(t, 1, p)
+
+	b, p, err = d.ReadUntilAny(m, 0)
+	casecheck.NoError(t, err)
+	casecheck.Equal(t, "line\r\n.\r\n", string(b))
+	casecheck.Equal(t, 2, p)
+
+	b, p, err = d.ReadUntilAny(m, 2)
+	casecheck.True(t, errors.Is(err, ErrScanLimit))
+	casecheck.Nil(t, b)
+	casecheck.Equal(t, -1, p)
+	casecheck.Equal(t, 4, d.Len())
+
+	b, p, err = d.ReadUntilAny(m, 4)
+	casecheck.NoError(t, err)
+	casecheck.Equal(t, "tail", string(b))
+	casecheck.Equal(t, -1, p)
+
+	_, _, err = d.ReadUntilAny(m, 0)
+	casecheck.True(t, errors.Is(err, io.EOF))
+
+	for _, limit := range []int{0, 3, 5, 7} {
+		d.Reset()
+		d.WriteString("ab\r\n.\r\n")
+		b, p, err = d.ReadUntilAny(m, limit)
+		casecheck.NoError(t, err)
+		casecheck.Equal(t, "ab\r\n.\r\n", string(b), limit)
+		casecheck.Equal(t, 2, p, limit)
+	}
+
+	d.Reset()
+	d.WriteString("abc\n")
+	_, _, err = d.ReadUntilAny(m, 2)
+	casecheck.True(t, errors.Is(err, ErrScanLimit))
+}
+
+func Benchmark_MatcherIndex(b *testing.B) {
+	m, err := NewMatcher([]byte("\r\n"), []byte("\n"), []byte("\r\n.\r\n"))
+	if err != nil {
+		b.Fatal(err)
+	}
+	data := make([]byte, 4096)
+	for i := range data {
+		data[i] = 'a'
+	}
+	data = append(data, "\r\n.\r\n"...)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		m.Index(data)
+	}
+}
@@ -0,0 +1,157 @@
+/*
+ *  Copyright (c) 2024-2025 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package data
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+type (
+	Position struct {
+		Offset int
+		// Line number starting from 1
+		Line int
+		// Column number in runes starting from 1
+		Column int
+		// Rune offset from the buffer start
+		Rune int
+	}
+
+	// LineIndex tracks line starts of the buffer. New data is indexed
+	// incrementally, the index is rebuilt after the buffer is reset or edited.
+	LineIndex struct {
+		buf    *Buffer
+		starts []int
+		runes  []int
+		done   int
+		count  int
+		edits  int
+	}
+)
+
+func NewLineIndex(b *Buffer) *LineIndex {
+	v := &LineIndex{buf: b}
+	v.Reset()
+	return v
+}
+
+func (v *LineIndex) Reset() {
+	v.starts = append(v.starts[:0], 0)
+	v.runes = append(v.runes[:0], 0)
+	v.done = 0
+	v.count = 0
+	v.edits = v.buf.edits
+}
+
+func (v *LineIndex) update() {
+	v.buf.flat()
+
+	if v.buf.edits != v.edits || v.buf.Size() < v.done {
+		v.Reset()
+	}
+
+	b := v.buf.buf
+	for v.done < len(b) {
+		if !utf8.FullRune(b[v.done:]) {
+			break
+		}
+
+		c := b[v.done]
+		if c < utf8.RuneSelf {
+			v.done++
+		} else {
+			_, n := utf8.DecodeRune(b[v.done:])
+			v.done += n
+		}
+		v.count++
+
+		if c == '\n' {
+			v.starts = append(v.starts, v.done)
+			v.runes = append(v.runes, v.count)
+		}
+	}
+}
+
+func (v *LineIndex) line(off int) int {
+	v.update()
+	return sort.Search(len(v.starts), func(i int) bool { return v.starts[i] > off }) - 1
+}
+
+func (v *LineIndex) Position(off int) Position {
+	off = max(0, min(off, v.buf.Size()))
+
+	i := v.line(off)
+	col := utf8.RuneCount(v.buf.buf[v.starts[i]:off])
+
+	return Position{
+		Offset: off,
+		Line:   i + 1,
+		Column: col + 1,
+		Rune:   v.runes[i] + col,
+	}
+}
+
+// Current returns the position of the read cursor.
+func (v *LineIndex) Current() Position {
+	return v.Position(v.buf.pos)
+}
+
+// Lines returns the number of lines indexed so far.
+func (v *LineIndex) Lines() int {
+	v.update()
+	return len(v.starts)
+}
+
+// Context returns the line of the offset and a caret under its column.
+// Lines longer than width runes are cut around the column, width <= 0 disables it.
+func (v *LineIndex) Context(off, width int) string {
+	pos := v.Position(off)
+	start := v.starts[pos.Line-1]
+
+	line := v.buf.buf[start:]
+	if i := bytes.IndexByte(line, '\n'); i >= 0 {
+		line = line[:i]
+	}
+	line = bytes.TrimSuffix(line, []byte{'\r'})
+
+	runes := []rune(string(line))
+	col := min(pos.Column-1, len(runes))
+	prefix, suffix := "", ""
+
+	if width > 0 && len(runes) > width {
+		from := max(0, min(col-width/2, len(runes)-width))
+		to := from + width
+		if from > 0 {
+			prefix = "..."
+		}
+		if to < len(runes) {
+			suffix = "..."
+		}
+		runes = runes[from:to]
+		col -= from
+	}
+
+	sb := strings.Builder{}
+	sb.Grow(2*len(runes) + 2*len(prefix) + len(suffix) + 2)
+	sb.WriteString(prefix)
+	sb.WriteString(string(runes))
+	sb.WriteString(suffix)
+	sb.WriteByte('\n')
+	sb.WriteString(strings.Repeat(" ", len(prefix)))
+	for _, r := range runes[:col] {
+		if r == '\t' {
+			sb.WriteByte('\t')
+		} else {
+			sb.WriteByte(' ')
+		}
+	}
+	sb.WriteByte('^')
+
+	return sb.String()
+}
@@ -0,0 +1,60 @@
+/*
+ *  Copyright (c) 2024-2025 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package data
+
+import (
+	"testing"
+
+	"go.osspkg.com/casecheck"
+)
+
+func TestUnit_LineIndex(t *testing.T) {
+	d := NewBuffer(10)
+	d.WriteString("ab\nФx")
+
+	li := NewLineIndex(d)
+	casecheck.Equal(t, Position{Offset: 0, Line: 1, Column: 1, Rune: 0}, li.Current())
+	casecheck.Equal(t, Position{Offset: 2, Line: 1, Column: 3, Rune: 2}, li.Position(2))
+	casecheck.Equal(t, Position{Offset: 5, Line: 2, Column: 2, Rune: 4}, li.Position(5))
+	casecheck.Equal(t, 2, li.Lines())
+
+	d.WriteString("\n\tkey = value\r\n")
+	d.Seek(12, SeekStart)
+	casecheck.Equal(t, Position{Offset: 12, Line: 3, Column: 6, Rune: 11}, li.Current())
+	casecheck.Equal(t, 4, li.Lines())
+	casecheck.Equal(t, "\tkey = value\n\t    ^", li.Context(12, 0))
+	casecheck.Equal(t, Position{Offset: 21, Line: 4, Column: 1, Rune: 20}, li.Position(100))
+
+	d.Reset()
+	d.WriteString("0123456789abcdef")
+	casecheck.Equal(t, "...234567...\n      ^", li.Context(5, 6))
+	casecheck.Equal(t, "012345...\n^", li.Context(0, 6))
+	casecheck.Equal(t, "...abcdef\n      ^", li.Context(13, 6))
+}
+
+func TestUnit_LineIndexEdits(t *testing.T) {
+	d := NewBuffer(10)
+	d.WriteString("a\nb\nc\nd\ne\nf\n")
+
+	li := NewLineIndex(d)
+	casecheck.Equal(t, 7, li.Lines())
+
+	d.Reset()
+	d.WriteString("xxxxxxxxxx\nyy")
+	casecheck.Equal(t, Position{Offset: 12, Line: 2, Column: 2, Rune: 12}, li.Position(12))
+
+	d.InsertAt(2, []byte("\n"))
+	casecheck.Equal(t, Position{Offset: 12, Line: 3, Column: 1, Rune: 12}, li.Position(12))
+
+	d.DeleteRange(2, 1)
+	d.InsertAt(0, []byte("z"))
+	casecheck.Equal(t, Position{Offset: 12, Line: 2, Column: 1, Rune: 12}, li.Position(12))
+
+	d.Seek(11, SeekStart)
+	d.Compact()
+	casecheck.Equal(t, Position{Offset: 1, Line: 2, Column: 1, Rune: 1}, li.Position(1))
+	casecheck.Equal(t, 2, li.Lines())
+}
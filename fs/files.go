@@ -130,3 +130,33 @@ func CopyFile(dst, src string, mode os.FileMode) error {
 	_, err = io.Copy(dist, source)
 	return err
 }
+
+// WriteFileAtomic writes the file through a temporary file in the same dir which
+// replaces the target only after successful write, so the file is never truncated.
+func WriteFileAtomic(filename string, mode os.FileMode, call func(w io.Writer) error) (err error) {
+	tmp, err := os.CreateTemp(filepath.Dir(filename), "."+filepath.Base(filename)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tmp.Close()           // nolint: errcheck
+			os.Remove(tmp.Name()) // nolint: errcheck
+		}
+	}()
+
+	if err = call(tmp); err != nil {
+		return err
+	}
+	if err = tmp.Chmod(mode); err != nil {
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), filename)
+}
@@ -17,7 +17,9 @@ func Pipe(w io.Writer, r io.Reader, size int) (n int, err error) {
 		return 0, fmt.Errorf("invalid buffer size")
 	}
 
-	buf := make([]byte, size)
+	pb := bytesPool.Get(size)
+	defer bytesPool.Put(pb)
+	buf := pb.B
 
 	for {
 		rn, re := r.Read(buf)
@@ -0,0 +1,62 @@
+/*
+ *  Copyright (c) 2024-2025 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package pool
+
+// Arena hands out pointers to objects from chunked backing slices and
+// releases all of them at once with Reset. Pointers must not be used after
+// Reset. It is not safe for concurrent use.
+type Arena[T any] struct {
+	chunks    [][]T
+	chunk     int
+	used      int
+	chunkSize int
+}
+
+func NewArena[T any](chunkSize int) *Arena[T] {
+	return &Arena[T]{chunkSize: max(chunkSize, 1)}
+}
+
+// New returns a pointer to a zero object.
+func (v *Arena[T]) New() *T {
+	if len(v.chunks) == 0 {
+		v.chunks = append(v.chunks, make([]T, v.chunkSize))
+	}
+
+	if v.used == len(v.chunks[v.chunk]) {
+		v.chunk++
+		v.used = 0
+		if v.chunk == len(v.chunks) {
+			v.chunks = append(v.chunks, make([]T, v.chunkSize))
+		}
+	}
+
+	t := &v.chunks[v.chunk][v.used]
+	v.used++
+
+	return t
+}
+
+// Len returns the number of objects handed out since the last Reset.
+func (v *Arena[T]) Len() int {
+	return v.chunk*v.chunkSize + v.used
+}
+
+func (v *Arena[T]) Reset() {
+	for i := 0; i < v.chunk && i < len(v.chunks); i++ {
+		clear(v.chunks[i])
+	}
+	if v.chunk < len(v.chunks) {
+		clear(v.chunks[v.chunk][:v.used])
+	}
+
+	v.chunk, v.used = 0, 0
+}
+
+func NewArenaPool[T any](chunkSize int, opts ...Option[*Arena[T]]) *Pool[*Arena[T]] {
+	return New(func() *Arena[T] {
+		return NewArena[T](chunkSize)
+	}, opts...)
+}
@@ -0,0 +1,108 @@
+/*
+ *  Copyright (c) 2024-2025 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package pool
+
+import (
+	"testing"
+
+	"go.osspkg.com/casecheck"
+)
+
+type arenaItem struct {
+	ID   int
+	Name string
+	Next *arenaItem
+}
+
+func (v *arenaItem) Reset() {
+	*v = arenaItem{}
+}
+
+func TestUnit_Arena(t *testing.T) {
+	a := NewArena[arenaItem](2)
+
+	items := make([]*arenaItem, 0, 5)
+	for i := 0; i < 5; i++ {
+		item := a.New()
+		casecheck.Equal(t, arenaItem{}, *item)
+		item.ID = i
+		items = append(items, item)
+	}
+	casecheck.Equal(t, 5, a.Len())
+	casecheck.Equal(t, 3, len(a.chunks))
+
+	for i, item := range items {
+		casecheck.Equal(t, i, item.ID)
+	}
+
+	a.Reset()
+	casecheck.Equal(t, 0, a.Len())
+	for _, item := range items {
+		casecheck.Equal(t, arenaItem{}, *item)
+	}
+
+	item := a.New()
+	casecheck.True(t, item == items[0])
+	casecheck.Equal(t, 3, len(a.chunks))
+}
+
+func TestUnit_ArenaPool(t *testing.T) {
+	p := NewArenaPool[arenaItem](16)
+
+	a := p.Get()
+	a.New().ID = 1
+	casecheck.Equal(t, 1, a.Len())
+	p.Put(a)
+
+	a = p.Get()
+	casecheck.Equal(t, 0, a.Len())
+}
+
+const benchItems = 64
+
+func Benchmark_ArenaPool(b *testing.B) {
+	p := NewArenaPool[arenaItem](benchItems)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			a := p.Get()
+			var prev *arenaItem
+			for i := 0; i < benchItems; i++ {
+				item := a.New()
+				item.ID, item.Next = i, prev
+				prev = item
+			}
+			p.Put(a)
+		}
+	})
+}
+
+func Benchmark_ObjectPool(b *testing.B) {
+	p := New[*arenaItem](func() *arenaItem { return &arenaItem{} })
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		items := make([]*arenaItem, 0, benchItems)
+		for pb.Next() {
+			var prev *arenaItem
+			for i := 0; i < benchItems; i++ {
+				item := p.Get()
+				item.ID, item.Next = i, prev
+				prev = item
+				items = append(items, item)
+			}
+			for _, item := range items {
+				p.Put(item)
+			}
+			items = items[:0]
+		}
+	})
+}
@@ -0,0 +1,104 @@
+/*
+ *  Copyright (c) 2024-2025 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package pool
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// Bounded keeps at most size objects. Objects are created lazily on demand
+// or eagerly in the constructor and live until the pool is dropped.
+type Bounded[T TPool] struct {
+	callNew func() T
+	idle    chan T
+	slots   chan struct{}
+	inUse   atomic.Int64
+}
+
+func NewBounded[T TPool](size int, eager bool, callNew func() T) *Bounded[T] {
+	if size <= 0 {
+		panic("NewBounded: size <= 0")
+	}
+
+	v := &Bounded[T]{
+		callNew: callNew,
+		idle:    make(chan T, size),
+		slots:   make(chan struct{}, size),
+	}
+
+	for i := 0; i < size; i++ {
+		if eager {
+			v.idle <- callNew()
+		} else {
+			v.slots <- struct{}{}
+		}
+	}
+
+	return v
+}
+
+// Acquire returns an idle object, creates a new one if the pool is not full
+// or waits until an object is released or the context is done.
+func (v *Bounded[T]) Acquire(ctx context.Context) (T, error) {
+	if t, ok := v.TryAcquire(); ok {
+		return t, nil
+	}
+
+	select {
+	case t := <-v.idle:
+		v.inUse.Add(1)
+		return t, nil
+	case <-v.slots:
+		v.inUse.Add(1)
+		return v.callNew(), nil
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+func (v *Bounded[T]) TryAcquire() (T, bool) {
+	select {
+	case t := <-v.idle:
+		v.inUse.Add(1)
+		return t, true
+	default:
+	}
+
+	select {
+	case <-v.slots:
+		v.inUse.Add(1)
+		return v.callNew(), true
+	default:
+		var zero T
+		return zero, false
+	}
+}
+
+func (v *Bounded[T]) Release(t T) {
+	t.Reset()
+
+	select {
+	case v.idle <- t:
+		v.inUse.Add(-1)
+	default:
+	}
+}
+
+// InUse returns the number of acquired objects.
+func (v *Bounded[T]) InUse() int {
+	return int(v.inUse.Load())
+}
+
+// Idle returns the number of created objects waiting in the pool.
+func (v *Bounded[T]) Idle() int {
+	return len(v.idle)
+}
+
+func (v *Bounded[T]) Cap() int {
+	return cap(v.idle)
+}
@@ -0,0 +1,96 @@
+/*
+ *  Copyright (c) 2024-2025 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package pool
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.osspkg.com/casecheck"
+)
+
+func TestUnit_BoundedLazy(t *testing.T) {
+	var created atomic.Int32
+	p := NewBounded[*bytes.Buffer](2, false, func() *bytes.Buffer {
+		created.Add(1)
+		return &bytes.Buffer{}
+	})
+	casecheck.Equal(t, int32(0), created.Load())
+	casecheck.Equal(t, 0, p.Idle())
+	casecheck.Equal(t, 2, p.Cap())
+
+	b1, err := p.Acquire(context.TODO())
+	casecheck.NoError(t, err)
+	b2, ok := p.TryAcquire()
+	casecheck.True(t, ok)
+	casecheck.Equal(t, int32(2), created.Load())
+	casecheck.Equal(t, 2, p.InUse())
+
+	_, ok = p.TryAcquire()
+	casecheck.False(t, ok)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = p.Acquire(ctx)
+	casecheck.True(t, errors.Is(err, context.DeadlineExceeded))
+
+	b1.WriteString("data")
+	p.Release(b1)
+	casecheck.Equal(t, 1, p.InUse())
+	casecheck.Equal(t, 1, p.Idle())
+
+	b3, err := p.Acquire(context.TODO())
+	casecheck.NoError(t, err)
+	casecheck.Equal(t, 0, b3.Len())
+	casecheck.Equal(t, int32(2), created.Load())
+
+	p.Release(b2)
+	p.Release(b3)
+	casecheck.Equal(t, 0, p.InUse())
+	casecheck.Equal(t, 2, p.Idle())
+}
+
+func TestUnit_BoundedEager(t *testing.T) {
+	var created atomic.Int32
+	p := NewBounded[*bytes.Buffer](3, true, func() *bytes.Buffer {
+		created.Add(1)
+		return &bytes.Buffer{}
+	})
+	casecheck.Equal(t, int32(3), created.Load())
+	casecheck.Equal(t, 3, p.Idle())
+
+	var (
+		wg  sync.WaitGroup
+		max atomic.Int64
+	)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			b, err := p.Acquire(context.TODO())
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if n := int64(p.InUse()); n > max.Load() {
+				max.Store(n)
+			}
+			time.Sleep(time.Millisecond)
+			p.Release(b)
+		}()
+	}
+	wg.Wait()
+
+	casecheck.Equal(t, int32(3), created.Load())
+	casecheck.True(t, max.Load() <= 3)
+	casecheck.Equal(t, 0, p.InUse())
+}
@@ -0,0 +1,110 @@
+/*
+ *  Copyright (c) 2024-2025 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package pool
+
+import (
+	"reflect"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	// Leak is an object taken from the pool and not returned yet.
+	Leak struct {
+		Object any
+		Stack  string
+		Since  time.Time
+	}
+
+	record struct {
+		pcs   []uintptr
+		since time.Time
+	}
+
+	tracker struct {
+		list map[any]record
+		mux  sync.Mutex
+	}
+)
+
+// OptDebug enables counters and records the caller stack of every Get.
+// Objects which are not taken from the pool or put twice are dropped on Put.
+// Only objects of comparable non-interface types like pointers are tracked.
+func OptDebug[T TPool]() Option[T] {
+	return func(v *Pool[T]) {
+		if v.stats == nil {
+			v.stats = &counters{}
+		}
+		if rt := reflect.TypeFor[T](); rt.Comparable() && rt.Kind() != reflect.Interface {
+			v.debug = &tracker{list: make(map[any]record, 10)}
+		}
+	}
+}
+
+func (v *tracker) get(obj any) {
+	pcs := make([]uintptr, 32)
+	pcs = pcs[:runtime.Callers(3, pcs)]
+
+	v.mux.Lock()
+	defer v.mux.Unlock()
+
+	v.list[obj] = record{pcs: pcs, since: time.Now()}
+}
+
+func (v *tracker) put(obj any) bool {
+	v.mux.Lock()
+	defer v.mux.Unlock()
+
+	if _, ok := v.list[obj]; !ok {
+		return false
+	}
+	delete(v.list, obj)
+
+	return true
+}
+
+// Audit returns objects taken from the pool and not returned yet, the oldest
+// first. It is empty if OptDebug is not set.
+func (v *Pool[T]) Audit() []Leak {
+	if v.debug == nil {
+		return nil
+	}
+
+	v.debug.mux.Lock()
+	result := make([]Leak, 0, len(v.debug.list))
+	for obj, rec := range v.debug.list {
+		result = append(result, Leak{Object: obj, Since: rec.since, Stack: formatStack(rec.pcs)})
+	}
+	v.debug.mux.Unlock()
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Since.Before(result[j].Since)
+	})
+
+	return result
+}
+
+func formatStack(pcs []uintptr) string {
+	sb := strings.Builder{}
+	frames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := frames.Next()
+		sb.WriteString(frame.Function)
+		sb.WriteString("\n\t")
+		sb.WriteString(frame.File)
+		sb.WriteByte(':')
+		sb.WriteString(strconv.Itoa(frame.Line))
+		sb.WriteByte('\n')
+		if !more {
+			break
+		}
+	}
+	return sb.String()
+}
@@ -5,7 +5,10 @@
 
 package pool
 
-import "sync"
+import (
+	"sync"
+	"sync/atomic"
+)
 
 type TPool interface {
 	Reset()
@@ -14,12 +17,29 @@ type TPool interface {
 type Pool[T TPool] struct {
 	callNew func() T
 	pool    sync.Pool
+	stats   *counters
+	debug   *tracker
+	onGet   []func(T)
+	onPut   []func(T) bool
+	trim    func(T)
 }
 
-func New[T TPool](callNew func() T) *Pool[T] {
-	return &Pool[T]{
-		pool: sync.Pool{New: func() any { return callNew() }},
+type Option[T TPool] func(*Pool[T])
+
+func New[T TPool](callNew func() T, opts ...Option[T]) *Pool[T] {
+	v := &Pool[T]{callNew: callNew}
+	v.pool.New = func() any {
+		if v.stats != nil {
+			v.stats.news.Add(1)
+		}
+		return v.callNew()
+	}
+
+	for _, opt := range opts {
+		opt(v)
 	}
+
+	return v
 }
 
 func (v *Pool[T]) Get() T {
@@ -27,10 +47,99 @@ func (v *Pool[T]) Get() T {
 	if !ok {
 		buf = v.callNew()
 	}
+
+	if v.stats != nil {
+		v.stats.gets.Add(1)
+	}
+	if v.debug != nil {
+		v.debug.get(buf)
+	}
+	for _, fn := range v.onGet {
+		fn(buf)
+	}
+
 	return buf
 }
 
 func (v *Pool[T]) Put(t T) {
-	t.Reset()
+	if v.debug != nil && !v.debug.put(t) {
+		v.stats.drops.Add(1)
+		return
+	}
+
+	for _, fn := range v.onPut {
+		if !fn(t) {
+			if v.stats != nil {
+				v.stats.drops.Add(1)
+			}
+			return
+		}
+	}
+
+	if v.stats != nil {
+		v.stats.puts.Add(1)
+	}
+
+	if v.trim != nil {
+		v.trim(t)
+	} else {
+		t.Reset()
+	}
 	v.pool.Put(t)
 }
+
+// OptOnGet calls fn for every object returned by Get.
+func OptOnGet[T TPool](fn func(T)) Option[T] {
+	return func(v *Pool[T]) {
+		v.onGet = append(v.onGet, fn)
+	}
+}
+
+// OptOnPut calls fn before an object is stored, the object is dropped
+// if fn returns false. Hooks are called in the order they are set.
+func OptOnPut[T TPool](fn func(T) bool) Option[T] {
+	return func(v *Pool[T]) {
+		v.onPut = append(v.onPut, fn)
+	}
+}
+
+// OptTrim replaces the Reset call on Put.
+func OptTrim[T TPool](fn func(T)) Option[T] {
+	return func(v *Pool[T]) {
+		v.trim = fn
+	}
+}
+
+type (
+	Stats struct {
+		Gets  uint64
+		Puts  uint64
+		News  uint64
+		Drops uint64
+	}
+
+	counters struct {
+		gets, puts, news, drops atomic.Uint64
+	}
+)
+
+// OptStats enables counters of the pool.
+func OptStats[T TPool]() Option[T] {
+	return func(v *Pool[T]) {
+		v.stats = &counters{}
+	}
+}
+
+// Stats returns zero counters if OptStats or OptDebug is not set.
+func (v *Pool[T]) Stats() Stats {
+	if v.stats == nil {
+		return Stats{}
+	}
+
+	return Stats{
+		Gets:  v.stats.gets.Load(),
+		Puts:  v.stats.puts.Load(),
+		News:  v.stats.news.Load(),
+		Drops: v.stats.drops.Load(),
+	}
+}
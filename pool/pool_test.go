@@ -8,6 +8,7 @@ package pool
 import (
 	"bytes"
 	"io"
+	"strings"
 	"sync"
 	"testing"
 
@@ -66,3 +67,100 @@ func TestUnit_PoolBytesBuffer(t *testing.T) {
 
 	wg.Wait()
 }
+
+func TestUnit_PoolStats(t *testing.T) {
+	bp := New[*bytes.Buffer](func() *bytes.Buffer {
+		return &bytes.Buffer{}
+	}, OptStats[*bytes.Buffer]())
+
+	b := bp.Get()
+	bp.Put(b)
+	bp.Get()
+
+	st := bp.Stats()
+	casecheck.Equal(t, uint64(2), st.Gets)
+	casecheck.Equal(t, uint64(1), st.Puts)
+	casecheck.True(t, st.News >= 1 && st.News <= 2)
+	casecheck.Equal(t, uint64(0), st.Drops)
+	casecheck.Nil(t, bp.Audit())
+
+	casecheck.Equal(t, Stats{}, New(func() *bytes.Buffer { return nil }).Stats())
+}
+
+func TestUnit_PoolDebug(t *testing.T) {
+	bp := New[*bytes.Buffer](func() *bytes.Buffer {
+		return &bytes.Buffer{}
+	}, OptDebug[*bytes.Buffer]())
+
+	b1 := bp.Get()
+	b2 := bp.Get()
+	bp.Put(b1)
+	bp.Put(b1)
+	bp.Put(&bytes.Buffer{})
+
+	leaks := bp.Audit()
+	casecheck.Equal(t, 1, len(leaks))
+	casecheck.True(t, leaks[0].Object == any(b2))
+	casecheck.True(t, strings.Contains(leaks[0].Stack, "TestUnit_PoolDebug"))
+
+	st := bp.Stats()
+	casecheck.Equal(t, uint64(2), st.Gets)
+	casecheck.Equal(t, uint64(1), st.Puts)
+	casecheck.Equal(t, uint64(2), st.Drops)
+
+	bp.Put(b2)
+	casecheck.Equal(t, 0, len(bp.Audit()))
+}
+
+func TestUnit_PoolHooks(t *testing.T) {
+	gets, trims := 0, 0
+	bp := New[*bytes.Buffer](func() *bytes.Buffer {
+		return &bytes.Buffer{}
+	},
+		OptStats[*bytes.Buffer](),
+		OptOnGet(func(b *bytes.Buffer) {
+			gets++
+			b.WriteString("init")
+		}),
+		OptOnPut(func(b *bytes.Buffer) bool {
+			return b.Cap() <= 1024
+		}),
+		OptTrim(func(b *bytes.Buffer) {
+			trims++
+			b.Reset()
+		}),
+	)
+
+	b := bp.Get()
+	casecheck.Equal(t, "init", b.String())
+	bp.Put(b)
+
+	b = bp.Get()
+	b.Grow(4096)
+	bp.Put(b)
+
+	casecheck.Equal(t, 2, gets)
+	casecheck.Equal(t, 1, trims)
+	casecheck.Equal(t, uint64(1), bp.Stats().Drops)
+	casecheck.Equal(t, uint64(1), bp.Stats().Puts)
+}
+
+func TestUnit_SlicePoolZero(t *testing.T) {
+	sp := NewSlicePool[*int](0, 4)
+
+	s := sp.Get()
+	i := 1
+	s.B = append(s.B, &i, &i)
+	sp.Put(s)
+
+	casecheck.Equal(t, 0, len(s.B))
+	for _, p := range s.B[:cap(s.B)] {
+		casecheck.Nil(t, p)
+	}
+
+	bs := NewSlicePool[byte](0, 4)
+	b := bs.Get()
+	b.B = append(b.B, 1, 2)
+	bs.Put(b)
+	casecheck.Equal(t, byte(1), b.B[:2][0])
+}
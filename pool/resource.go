@@ -0,0 +1,323 @@
+/*
+ *  Copyright (c) 2024-2025 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package pool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+var ErrPoolClosed = errors.New("pool is closed")
+
+type (
+	Clock interface {
+		Now() time.Time
+		After(d time.Duration) <-chan time.Time
+	}
+
+	realClock struct{}
+
+	ResourceConfig[T any] struct {
+		// Factory creates a new resource, required
+		Factory func(ctx context.Context) (T, error)
+		// Destroy closes the resource
+		Destroy func(T) error
+		// Validate checks an idle resource before it is borrowed
+		Validate func(ctx context.Context, t T) error
+		// OnError gets errors of the background warming and destroying
+		OnError func(err error)
+		// MaxSize of created resources, required
+		MaxSize int
+		// MinIdle resources kept warm by the reaper
+		MinIdle int
+		// IdleTimeout after which an idle resource over MinIdle is destroyed, 0 disables it
+		IdleTimeout time.Duration
+		// MaxLifetime after which a resource is destroyed, 0 disables it
+		MaxLifetime time.Duration
+		// ReapInterval of the background reaper, 0 disables it
+		ReapInterval time.Duration
+		// Clock is the time source, the system clock by default
+		Clock Clock
+	}
+
+	Resource[T any] struct {
+		Value   T
+		created time.Time
+		used    time.Time
+		pool    *ResourcePool[T]
+	}
+
+	// ResourcePool keeps closable resources like connections or processes.
+	ResourcePool[T any] struct {
+		conf   ResourceConfig[T]
+		idle   chan *Resource[T]
+		slots  chan struct{}
+		inUse  atomic.Int64
+		closed atomic.Bool
+		done   chan struct{}
+		// ctx of background warming, cancelled on Close
+		ctx    context.Context
+		cancel context.CancelFunc
+		reaped chan struct{}
+	}
+)
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+func NewResourcePool[T any](conf ResourceConfig[T]) (*ResourcePool[T], error) {
+	if conf.Factory == nil {
+		return nil, fmt.Errorf("factory is nil")
+	}
+	if conf.MaxSize <= 0 {
+		return nil, fmt.Errorf("invalid max size: %d", conf.MaxSize)
+	}
+	if conf.MinIdle > conf.MaxSize {
+		return nil, fmt.Errorf("min idle %d is greater than max size %d", conf.MinIdle, conf.MaxSize)
+	}
+	if conf.Clock == nil {
+		conf.Clock = realClock{}
+	}
+
+	v := &ResourcePool[T]{
+		conf:   conf,
+		idle:   make(chan *Resource[T], conf.MaxSize),
+		slots:  make(chan struct{}, conf.MaxSize),
+		done:   make(chan struct{}),
+		reaped: make(chan struct{}),
+	}
+	v.ctx, v.cancel = context.WithCancel(context.Background())
+
+	for i := 0; i < conf.MaxSize; i++ {
+		v.slots <- struct{}{}
+	}
+
+	if conf.ReapInterval > 0 {
+		go v.reaper()
+	} else {
+		close(v.reaped)
+	}
+
+	return v, nil
+}
+
+// Borrow returns a valid idle resource, creates a new one if the pool is not
+// full or waits until a resource is released or the context is done.
+func (v *ResourcePool[T]) Borrow(ctx context.Context) (*Resource[T], error) {
+	for {
+		if v.closed.Load() {
+			return nil, ErrPoolClosed
+		}
+
+		r, err := v.take(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if r != nil && v.closed.Load() {
+			v.idle <- r
+			return nil, ErrPoolClosed
+		}
+
+		if r == nil {
+			if v.closed.Load() {
+				v.slots <- struct{}{}
+				return nil, ErrPoolClosed
+			}
+			if r, err = v.create(ctx); err != nil {
+				v.slots <- struct{}{}
+				return nil, err
+			}
+			v.inUse.Add(1)
+			return r, nil
+		}
+
+		// the resources kept warm for MinIdle are not destroyed by IdleTimeout
+		if v.expired(r, v.conf.Clock.Now(), len(v.idle) >= v.conf.MinIdle) {
+			v.destroy(r)
+			continue
+		}
+		if v.conf.Validate != nil {
+			if err = v.conf.Validate(ctx, r.Value); err != nil {
+				v.destroy(r)
+				continue
+			}
+		}
+
+		v.inUse.Add(1)
+		return r, nil
+	}
+}
+
+// take returns an idle resource or nil if a slot for a new one is taken.
+func (v *ResourcePool[T]) take(ctx context.Context) (*Resource[T], error) {
+	select {
+	case r := <-v.idle:
+		return r, nil
+	default:
+	}
+
+	select {
+	case r := <-v.idle:
+		return r, nil
+	case <-v.slots:
+		return nil, nil
+	case <-v.done:
+		return nil, ErrPoolClosed
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Release returns the resource to the pool.
+func (r *Resource[T]) Release() {
+	r.used = r.pool.conf.Clock.Now()
+	r.pool.inUse.Add(-1)
+	r.pool.idle <- r
+}
+
+// Destroy closes a broken resource instead of returning it to the pool.
+func (r *Resource[T]) Destroy() {
+	r.pool.inUse.Add(-1)
+	r.pool.destroy(r)
+}
+
+func (r *Resource[T]) CreatedAt() time.Time {
+	return r.created
+}
+
+func (v *ResourcePool[T]) InUse() int {
+	return int(v.inUse.Load())
+}
+
+func (v *ResourcePool[T]) Idle() int {
+	return len(v.idle)
+}
+
+// Close stops the reaper, waits until all borrowed resources are returned
+// and destroys them.
+func (v *ResourcePool[T]) Close(ctx context.Context) error {
+	if !v.closed.Swap(true) {
+		close(v.done)
+		v.cancel()
+	}
+
+	select {
+	case <-v.reaped:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	for n := 0; n < v.conf.MaxSize; {
+		select {
+		case r := <-v.idle:
+			v.destroyValue(r)
+			n++
+		case <-v.slots:
+			n++
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
+
+func (v *ResourcePool[T]) create(ctx context.Context) (*Resource[T], error) {
+	t, err := v.conf.Factory(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := v.conf.Clock.Now()
+	return &Resource[T]{Value: t, created: now, used: now, pool: v}, nil
+}
+
+func (v *ResourcePool[T]) expired(r *Resource[T], now time.Time, idle bool) bool {
+	if v.conf.MaxLifetime > 0 && now.Sub(r.created) >= v.conf.MaxLifetime {
+		return true
+	}
+	return idle && v.conf.IdleTimeout > 0 && now.Sub(r.used) >= v.conf.IdleTimeout
+}
+
+func (v *ResourcePool[T]) destroyValue(r *Resource[T]) {
+	if v.conf.Destroy == nil {
+		return
+	}
+	if err := v.conf.Destroy(r.Value); err != nil && v.conf.OnError != nil {
+		v.conf.OnError(fmt.Errorf("destroy resource: %w", err))
+	}
+}
+
+func (v *ResourcePool[T]) destroy(r *Resource[T]) {
+	v.destroyValue(r)
+	v.slots <- struct{}{}
+}
+
+func (v *ResourcePool[T]) reaper() {
+	defer close(v.reaped)
+
+	for {
+		v.reap()
+
+		select {
+		case <-v.done:
+			return
+		case <-v.conf.Clock.After(v.conf.ReapInterval):
+		}
+	}
+}
+
+// reap destroys expired idle resources and warms the pool up to MinIdle.
+func (v *ResourcePool[T]) reap() {
+	now := v.conf.Clock.Now()
+	kept := make([]*Resource[T], 0, len(v.idle))
+
+drain:
+	for n := len(v.idle); n > 0; n-- {
+		select {
+		case r := <-v.idle:
+			if v.expired(r, now, false) {
+				v.destroy(r)
+				continue
+			}
+			kept = append(kept, r)
+		default:
+			break drain
+		}
+	}
+
+	alive := len(kept)
+	for _, r := range kept {
+		if alive > v.conf.MinIdle && v.expired(r, now, true) {
+			v.destroy(r)
+			alive--
+			continue
+		}
+		v.idle <- r
+	}
+
+	for len(v.idle) < v.conf.MinIdle && !v.closed.Load() {
+		select {
+		case <-v.slots:
+		default:
+			return
+		}
+
+		r, err := v.create(v.ctx)
+		if err != nil {
+			v.slots <- struct{}{}
+			if v.conf.OnError != nil {
+				v.conf.OnError(fmt.Errorf("warm resource: %w", err))
+			}
+			return
+		}
+		v.idle <- r
+	}
+}
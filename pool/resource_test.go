@@ -0,0 +1,297 @@
+/*
+ *  Copyright (c) 2024-2025 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package pool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.osspkg.com/casecheck"
+)
+
+type fakeClock struct {
+	now    time.Time
+	timers []chan time.Time
+	mux    sync.Mutex
+}
+
+func (v *fakeClock) Now() time.Time {
+	v.mux.Lock()
+	defer v.mux.Unlock()
+	return v.now
+}
+
+func (v *fakeClock) After(time.Duration) <-chan time.Time {
+	v.mux.Lock()
+	defer v.mux.Unlock()
+	c := make(chan time.Time, 1)
+	v.timers = append(v.timers, c)
+	return c
+}
+
+func (v *fakeClock) Add(d time.Duration) {
+	v.mux.Lock()
+	defer v.mux.Unlock()
+	v.now = v.now.Add(d)
+}
+
+func (v *fakeClock) Tick() {
+	v.mux.Lock()
+	timers := v.timers
+	v.timers = nil
+	v.mux.Unlock()
+	for _, c := range timers {
+		c <- v.Now()
+	}
+}
+
+type fakeConn struct {
+	id     int
+	broken bool
+	closed atomic.Bool
+}
+
+type fakeFactory struct {
+	count  atomic.Int32
+	closed atomic.Int32
+	fail   atomic.Bool
+}
+
+func (v *fakeFactory) conf(clock Clock) ResourceConfig[*fakeConn] {
+	return ResourceConfig[*fakeConn]{
+		Factory: func(context.Context) (*fakeConn, error) {
+			if v.fail.Load() {
+				return nil, fmt.Errorf("factory fail")
+			}
+			return &fakeConn{id: int(v.count.Add(1))}, nil
+		},
+		Destroy: func(c *fakeConn) error {
+			c.closed.Store(true)
+			v.closed.Add(1)
+			return nil
+		},
+		Validate: func(_ context.Context, c *fakeConn) error {
+			if c.broken {
+				return fmt.Errorf("broken")
+			}
+			return nil
+		},
+		MaxSize: 2,
+		Clock:   clock,
+	}
+}
+
+func TestUnit_ResourcePoolBorrow(t *testing.T) {
+	ff := &fakeFactory{}
+	p, err := NewResourcePool(ff.conf(&fakeClock{}))
+	casecheck.NoError(t, err)
+
+	r1, err := p.Borrow(context.TODO())
+	casecheck.NoError(t, err)
+	r2, err := p.Borrow(context.TODO())
+	casecheck.NoError(t, err)
+	casecheck.Equal(t, 2, p.InUse())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = p.Borrow(ctx)
+	casecheck.True(t, errors.Is(err, context.DeadlineExceeded))
+
+	r1.Value.broken = true
+	r1.Release()
+	r3, err := p.Borrow(context.TODO())
+	casecheck.NoError(t, err)
+	casecheck.Equal(t, 3, r3.Value.id)
+	casecheck.True(t, r1.Value.closed.Load())
+
+	r2.Destroy()
+	casecheck.Equal(t, int32(2), ff.closed.Load())
+	casecheck.Equal(t, 1, p.InUse())
+
+	ff.fail.Store(true)
+	_, err = p.Borrow(context.TODO())
+	casecheck.Error(t, err)
+	ff.fail.Store(false)
+
+	done := make(chan error)
+	go func() { done <- p.Close(context.TODO()) }()
+
+	time.Sleep(10 * time.Millisecond)
+	_, err = p.Borrow(context.TODO())
+	casecheck.True(t, errors.Is(err, ErrPoolClosed))
+
+	r3.Release()
+	casecheck.NoError(t, <-done)
+	casecheck.True(t, r3.Value.closed.Load())
+	casecheck.Equal(t, int32(3), ff.closed.Load())
+}
+
+func TestUnit_ResourcePoolLifetime(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	ff := &fakeFactory{}
+	conf := ff.conf(clock)
+	conf.MaxSize = 3
+	conf.MinIdle = 1
+	conf.IdleTimeout = time.Minute
+	conf.MaxLifetime = time.Hour
+
+	p, err := NewResourcePool(conf)
+	casecheck.NoError(t, err)
+
+	p.reap()
+	casecheck.Equal(t, 1, p.Idle())
+
+	r1, err := p.Borrow(context.TODO())
+	casecheck.NoError(t, err)
+	r2, err := p.Borrow(context.TODO())
+	casecheck.NoError(t, err)
+	r1.Release()
+	r2.Release()
+	casecheck.Equal(t, 2, p.Idle())
+
+	clock.Add(2 * time.Minute)
+	p.reap()
+	casecheck.Equal(t, 1, p.Idle())
+	casecheck.Equal(t, int32(1), ff.closed.Load())
+
+	clock.Add(2 * time.Hour)
+	r, err := p.Borrow(context.TODO())
+	casecheck.NoError(t, err)
+	casecheck.Equal(t, 3, r.Value.id)
+	casecheck.Equal(t, int32(2), ff.closed.Load())
+	r.Release()
+
+	casecheck.NoError(t, p.Close(context.TODO()))
+	casecheck.Equal(t, int32(3), ff.closed.Load())
+}
+
+func TestUnit_ResourcePoolReaper(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	ff := &fakeFactory{}
+	conf := ff.conf(clock)
+	conf.MinIdle = 2
+	conf.ReapInterval = time.Second
+
+	p, err := NewResourcePool(conf)
+	casecheck.NoError(t, err)
+
+	for i := 0; i < 100 && p.Idle() < 2; i++ {
+		time.Sleep(time.Millisecond)
+		clock.Tick()
+	}
+	casecheck.Equal(t, 2, p.Idle())
+	casecheck.Equal(t, int32(2), ff.count.Load())
+
+	go func() {
+		for i := 0; i < 100; i++ {
+			time.Sleep(time.Millisecond)
+			clock.Tick()
+		}
+	}()
+	casecheck.NoError(t, p.Close(context.TODO()))
+	casecheck.Equal(t, int32(2), ff.closed.Load())
+}
+
+func TestUnit_ResourcePoolCloseWaiter(t *testing.T) {
+	ff := &fakeFactory{}
+	conf := ff.conf(&fakeClock{})
+	conf.MaxSize = 1
+
+	p, err := NewResourcePool(conf)
+	casecheck.NoError(t, err)
+
+	r, err := p.Borrow(context.TODO())
+	casecheck.NoError(t, err)
+
+	borrowed := make(chan error)
+	go func() {
+		_, err0 := p.Borrow(context.Background())
+		borrowed <- err0
+	}()
+
+	done := make(chan error)
+	time.Sleep(10 * time.Millisecond)
+	go func() { done <- p.Close(context.TODO()) }()
+
+	casecheck.True(t, errors.Is(<-borrowed, ErrPoolClosed))
+
+	r.Release()
+	casecheck.NoError(t, <-done)
+	casecheck.Equal(t, int32(1), ff.closed.Load())
+}
+
+func TestUnit_ResourcePoolWarmIdle(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	ff := &fakeFactory{}
+	conf := ff.conf(clock)
+	conf.MaxSize = 3
+	conf.MinIdle = 2
+	conf.IdleTimeout = time.Second
+
+	p, err := NewResourcePool(conf)
+	casecheck.NoError(t, err)
+
+	p.reap()
+	casecheck.Equal(t, 2, p.Idle())
+
+	clock.Add(2 * time.Second)
+	p.reap()
+	casecheck.Equal(t, 2, p.Idle())
+
+	r, err := p.Borrow(context.TODO())
+	casecheck.NoError(t, err)
+	casecheck.True(t, r.Value.id <= 2)
+	casecheck.Equal(t, int32(2), ff.count.Load())
+	casecheck.Equal(t, int32(0), ff.closed.Load())
+	r.Release()
+
+	casecheck.NoError(t, p.Close(context.TODO()))
+}
+
+func TestUnit_ResourcePoolCloseHangingFactory(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	started := make(chan struct{})
+
+	conf := ResourceConfig[*fakeConn]{
+		Factory: func(ctx context.Context) (*fakeConn, error) {
+			close(started)
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+		MaxSize:      1,
+		MinIdle:      1,
+		ReapInterval: time.Second,
+		Clock:        clock,
+	}
+
+	p, err := NewResourcePool(conf)
+	casecheck.NoError(t, err)
+	<-started
+
+	casecheck.NoError(t, p.Close(context.TODO()))
+
+	release := make(chan struct{})
+	defer close(release)
+	started = make(chan struct{})
+	conf.Factory = func(context.Context) (*fakeConn, error) {
+		close(started)
+		<-release
+		return nil, fmt.Errorf("closed")
+	}
+
+	p, err = NewResourcePool(conf)
+	casecheck.NoError(t, err)
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	casecheck.True(t, errors.Is(p.Close(ctx), context.DeadlineExceeded))
+}
@@ -0,0 +1,148 @@
+/*
+ *  Copyright (c) 2024-2025 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package pool
+
+import (
+	"math/bits"
+	"sync"
+
+	"go.osspkg.com/ioutils/data"
+)
+
+// sizeClasses splits sizes from minSize to maxSize into power-of-two classes.
+type sizeClasses struct {
+	minShift int
+	maxShift int
+	maxSize  int
+}
+
+func newSizeClasses(minSize, maxSize int) sizeClasses {
+	minSize = max(minSize, 1)
+	maxSize = max(maxSize, minSize)
+
+	return sizeClasses{
+		minShift: bits.Len(uint(minSize - 1)),
+		maxShift: bits.Len(uint(maxSize)) - 1,
+		maxSize:  maxSize,
+	}
+}
+
+func (v sizeClasses) count() int {
+	return max(v.maxShift-v.minShift+1, 1)
+}
+
+// get returns the smallest class fitting n or -1 if n is too big.
+func (v sizeClasses) get(n int) int {
+	shift := max(bits.Len(uint(max(n, 1)-1)), v.minShift)
+	if shift > v.maxShift {
+		return -1
+	}
+	return shift - v.minShift
+}
+
+// put returns the largest class served by the capacity c or -1.
+func (v sizeClasses) put(c int) int {
+	if c <= 0 || c > v.maxSize {
+		return -1
+	}
+	shift := min(bits.Len(uint(c))-1, v.maxShift)
+	if shift < v.minShift {
+		return -1
+	}
+	return shift - v.minShift
+}
+
+func (v sizeClasses) size(class int) int {
+	return 1 << (v.minShift + class)
+}
+
+// SizedPool keeps slices in power-of-two size classes. Slices larger than
+// maxSize are neither kept nor handed out of the pool.
+type SizedPool[T any] struct {
+	classes sizeClasses
+	pools   []sync.Pool
+}
+
+func NewSizedPool[T any](minSize, maxSize int) *SizedPool[T] {
+	v := &SizedPool[T]{classes: newSizeClasses(minSize, maxSize)}
+	v.pools = make([]sync.Pool, v.classes.count())
+
+	return v
+}
+
+// Get returns a slice with the length n and the capacity of the smallest
+// fitting class.
+func (v *SizedPool[T]) Get(n int) *Slice[T] {
+	n = max(n, 0)
+
+	class := v.classes.get(n)
+	if class < 0 {
+		return &Slice[T]{B: make([]T, n)}
+	}
+
+	if s, ok := v.pools[class].Get().(*Slice[T]); ok {
+		s.B = s.B[:n]
+		return s
+	}
+
+	return &Slice[T]{B: make([]T, n, v.classes.size(class))}
+}
+
+func (v *SizedPool[T]) Put(s *Slice[T]) {
+	if s == nil {
+		return
+	}
+
+	class := v.classes.put(cap(s.B))
+	if class < 0 {
+		return
+	}
+
+	s.Reset()
+	v.pools[class].Put(s)
+}
+
+// BufferPool keeps data.Buffer in power-of-two size classes by capacity.
+type BufferPool struct {
+	classes sizeClasses
+	pools   []sync.Pool
+}
+
+func NewBufferPool(minSize, maxSize int) *BufferPool {
+	v := &BufferPool{classes: newSizeClasses(minSize, maxSize)}
+	v.pools = make([]sync.Pool, v.classes.count())
+
+	return v
+}
+
+// Get returns an empty buffer with the capacity of at least n.
+func (v *BufferPool) Get(n int) *data.Buffer {
+	class := v.classes.get(n)
+	if class < 0 {
+		return data.NewBuffer(n)
+	}
+
+	if b, ok := v.pools[class].Get().(*data.Buffer); ok {
+		return b
+	}
+
+	return data.NewBuffer(v.classes.size(class))
+}
+
+func (v *BufferPool) Put(b *data.Buffer) {
+	if b == nil {
+		return
+	}
+
+	b.Reset()
+
+	class := v.classes.put(b.Cap())
+	if class < 0 {
+		return
+	}
+
+	v.pools[class].Put(b)
+}
@@ -0,0 +1,83 @@
+/*
+ *  Copyright (c) 2024-2025 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package pool
+
+import (
+	"testing"
+
+	"go.osspkg.com/casecheck"
+)
+
+func TestUnit_SizeClasses(t *testing.T) {
+	sc := newSizeClasses(100, 5000)
+	casecheck.Equal(t, 6, sc.count())
+	casecheck.Equal(t, 128, sc.size(0))
+	casecheck.Equal(t, 4096, sc.size(5))
+
+	casecheck.Equal(t, 0, sc.get(0))
+	casecheck.Equal(t, 0, sc.get(128))
+	casecheck.Equal(t, 1, sc.get(129))
+	casecheck.Equal(t, 5, sc.get(4096))
+	casecheck.Equal(t, -1, sc.get(4097))
+
+	casecheck.Equal(t, -1, sc.put(0))
+	casecheck.Equal(t, -1, sc.put(127))
+	casecheck.Equal(t, 0, sc.put(128))
+	casecheck.Equal(t, 0, sc.put(255))
+	casecheck.Equal(t, 5, sc.put(5000))
+	casecheck.Equal(t, -1, sc.put(5001))
+}
+
+func TestUnit_SizedPool(t *testing.T) {
+	p := NewSizedPool[byte](64, 1024)
+
+	s := p.Get(100)
+	casecheck.Equal(t, 100, len(s.B))
+	casecheck.Equal(t, 128, cap(s.B))
+	p.Put(s)
+
+	s = p.Get(10)
+	casecheck.Equal(t, 10, len(s.B))
+	casecheck.Equal(t, 64, cap(s.B))
+	p.Put(s)
+
+	s = p.Get(2000)
+	casecheck.Equal(t, 2000, len(s.B))
+	casecheck.Equal(t, 2000, cap(s.B))
+	p.Put(s)
+	p.Put(nil)
+}
+
+func TestUnit_BufferPool(t *testing.T) {
+	p := NewBufferPool(64, 1024)
+
+	b := p.Get(100)
+	casecheck.Equal(t, 0, b.Size())
+	casecheck.Equal(t, 128, b.Cap())
+	b.WriteString("data")
+	p.Put(b)
+
+	b = p.Get(2048)
+	casecheck.Equal(t, 2048, b.Cap())
+	p.Put(b)
+	p.Put(nil)
+}
+
+func Benchmark_SizedPool(b *testing.B) {
+	p := NewSizedPool[byte](512, 1<<20)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			s := p.Get(512 << (i % 8))
+			p.Put(s)
+			i++
+		}
+	})
+}
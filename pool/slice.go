@@ -5,6 +5,8 @@
 
 package pool
 
+import "reflect"
+
 type Slice[T any] struct {
 	B []T
 }
@@ -13,8 +15,30 @@ func (v *Slice[T]) Reset() {
 	v.B = v.B[:0]
 }
 
-func NewSlicePool[T any](l, c int) *Pool[*Slice[T]] {
+// NewSlicePool zeroes elements of the returned slices if they may hold references.
+func NewSlicePool[T any](l, c int, opts ...Option[*Slice[T]]) *Pool[*Slice[T]] {
+	if !isScalar(reflect.TypeFor[T]().Kind()) {
+		opts = append([]Option[*Slice[T]]{OptOnPut(zeroSlice[T])}, opts...)
+	}
+
 	return New(func() *Slice[T] {
 		return &Slice[T]{B: make([]T, l, c)}
-	})
+	}, opts...)
+}
+
+func zeroSlice[T any](v *Slice[T]) bool {
+	clear(v.B[:cap(v.B)])
+	return true
+}
+
+func isScalar(k reflect.Kind) bool {
+	switch k {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return true
+	default:
+		return false
+	}
 }